@@ -0,0 +1,238 @@
+package datapoint
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Area selects the sites included in an AreaSnapshot
+type Area interface {
+	Contains(site Site) bool
+}
+
+// AreaFunc adapts a function into an Area
+type AreaFunc func(site Site) bool
+
+// Contains returns the result of calling the function
+func (f AreaFunc) Contains(site Site) bool {
+	return f(site)
+}
+
+// BoundingBox is an area bounded by lines of latitude and longitude in WGS84 decimal degrees. Boxes crossing the
+// antimeridian are not supported
+type BoundingBox struct {
+	South float64
+	West  float64
+	North float64
+	East  float64
+}
+
+// Contains returns if the site lies within the box, including its edges
+func (b BoundingBox) Contains(site Site) bool {
+	return site.Latitude >= b.South && site.Latitude <= b.North && site.Longitude >= b.West && site.Longitude <= b.East
+}
+
+// Polygon is an area bounded by a ring of WGS84 positions. The ring does not need to be closed. Edges are treated as
+// straight lines in latitude and longitude, which is accurate enough for areas the size of the UK
+type Polygon []LatLon
+
+// Contains returns if the site lies within the polygon, using the even-odd rule
+func (p Polygon) Contains(site Site) bool {
+	inside := false
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		a, b := p[i], p[j]
+		if (a.Latitude > site.Latitude) != (b.Latitude > site.Latitude) {
+			crossing := (b.Longitude-a.Longitude)*(site.Latitude-a.Latitude)/(b.Latitude-a.Latitude) + a.Longitude
+			if site.Longitude < crossing {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// InRegion returns an area containing the sites in the region, e.g. 'sw', compared without regard to case
+func InRegion(region string) Area {
+	return AreaFunc(func(site Site) bool { return strings.EqualFold(site.Region, region) })
+}
+
+// InUnitaryAuthArea returns an area containing the sites in the unitary auth area, e.g. 'Devon', compared ignoring
+// case, accents and punctuation
+func InUnitaryAuthArea(area string) Area {
+	a := normaliseName(area)
+	return AreaFunc(func(site Site) bool { return normaliseName(site.UnitaryAuthArea) == a })
+}
+
+// AreaSite is a site included in an AreaSnapshot along with its forecast
+type AreaSite struct {
+	Site     Site
+	Forecast Forecast
+}
+
+// ParameterSummary summarises the values of a parameter across the sites of an AreaSnapshot
+type ParameterSummary struct {
+	ParameterDescriptor
+	// Count is the number of sites which have a value for the parameter
+	Count int
+	// Skipped is the number of sites whose value for the parameter was not of the type the parameter is summarised as,
+	// such as a string for a numeric parameter. These values are not included in the summary
+	Skipped int
+	// Min, Max and Mean summarise numeric parameters and are 0 for categorical parameters, or if no value was included
+	Min  float64
+	Max  float64
+	Mean float64
+	// Counts holds the number of sites with each value of a categorical parameter, such as the weather type,
+	// visibility or wind direction, and is nil for numeric parameters or if no value was included
+	Counts map[string]int
+}
+
+// AreaSnapshot is the forecast for every site within an area at a single time
+type AreaSnapshot struct {
+	// Time is the time the snapshot was requested for
+	Time time.Time
+	// Sites holds each site in the area which has a forecast valid at the time, ordered by site ID
+	Sites []AreaSite
+	// Summaries holds a summary of each parameter across the sites, keyed by the parameter name
+	Summaries map[string]ParameterSummary
+	// Missing holds the IDs of the forecasts which have no matching site in the site list, in ascending order. Their
+	// region and unitary auth area are not known, so they are never included by InRegion or InUnitaryAuthArea
+	Missing []int
+}
+
+// NewAreaSnapshot selects the forecasts valid at the time for the sites within the area. The details of each site,
+// such as its region, are taken from sites where the IDs match, otherwise from the forecast itself and the ID is listed
+// in Missing. Parameters are summarised according to their definitions in a new ParameterRegistry
+func NewAreaSnapshot(reps []SiteRep, sites []Site, area Area, at time.Time) AreaSnapshot {
+	return newAreaSnapshot(NewParameterRegistry(), reps, sites, area, at)
+}
+
+func newAreaSnapshot(registry *ParameterRegistry, reps []SiteRep, sites []Site, area Area, at time.Time) AreaSnapshot {
+	byId := make(map[int]Site, len(sites))
+	for _, site := range sites {
+		byId[site.Id] = site
+	}
+
+	snapshot := AreaSnapshot{Time: at, Summaries: map[string]ParameterSummary{}}
+	for _, rep := range reps {
+		site, ok := byId[rep.Location.Id]
+		if !ok {
+			snapshot.Missing = append(snapshot.Missing, rep.Location.Id)
+			site = Site{
+				Id:        rep.Location.Id,
+				Latitude:  rep.Location.Latitude,
+				Longitude: rep.Location.Longitude,
+				Name:      rep.Location.Name,
+				Elevation: rep.Location.Elevation,
+			}
+		}
+		if !area.Contains(site) {
+			continue
+		}
+		f, ok := rep.ForecastAt(at)
+		if !ok {
+			continue
+		}
+		snapshot.Sites = append(snapshot.Sites, AreaSite{Site: site, Forecast: f})
+	}
+	sort.SliceStable(snapshot.Sites, func(i, j int) bool {
+		return snapshot.Sites[i].Site.Id < snapshot.Sites[j].Site.Id
+	})
+	sort.Ints(snapshot.Missing)
+
+	categorical := map[string]bool{}
+	for _, s := range snapshot.Sites {
+		for _, p := range s.Forecast.IntParams {
+			snapshot.add(registry, categorical, p.ParameterDescriptor, p.Value)
+		}
+		for _, p := range s.Forecast.FloatParams {
+			snapshot.add(registry, categorical, p.ParameterDescriptor, p.Value)
+		}
+		for _, p := range s.Forecast.StringParams {
+			snapshot.add(registry, categorical, p.ParameterDescriptor, p.Value)
+		}
+	}
+	for name, summary := range snapshot.Summaries {
+		if categorical[name] {
+			continue
+		}
+		if summary.Count == 0 {
+			summary.Min, summary.Max = 0, 0
+		} else {
+			summary.Mean /= float64(summary.Count)
+		}
+		snapshot.Summaries[name] = summary
+	}
+	return snapshot
+}
+
+// add adds a value to the summary of the parameter. Registered parameters are summarised as described by their
+// definition, and values of a different type are counted in Skipped. Parameters which are not registered are
+// categorical if their first value is a string and numeric otherwise, and later values of the other kind are skipped.
+// categorical records the kind chosen for each parameter
+func (a *AreaSnapshot) add(registry *ParameterRegistry, categorical map[string]bool, descriptor ParameterDescriptor, value any) {
+	_, isString := value.(string)
+	isCategorical, known := categorical[descriptor.Name]
+	matches := true
+	if definition, ok := registry.Lookup(descriptor.Name); ok {
+		isCategorical = definition.categorical()
+		matches = definition.Type.holds(value)
+	} else if known {
+		matches = isCategorical == isString
+	} else {
+		isCategorical = isString
+	}
+	categorical[descriptor.Name] = isCategorical
+
+	summary, ok := a.Summaries[descriptor.Name]
+	if !ok {
+		summary = ParameterSummary{ParameterDescriptor: descriptor}
+		if !isCategorical {
+			summary.Min, summary.Max = math.Inf(1), math.Inf(-1)
+		}
+	}
+	switch {
+	case !matches:
+		summary.Skipped++
+	case isCategorical:
+		if summary.Counts == nil {
+			summary.Counts = map[string]int{}
+		}
+		summary.Count++
+		summary.Counts[fmt.Sprint(value)]++
+	default:
+		var v float64
+		switch n := value.(type) {
+		case int:
+			v = float64(n)
+		case float64:
+			v = n
+		}
+		// the running total is kept in Mean until every value has been added
+		summary.Count++
+		summary.Min = min(summary.Min, v)
+		summary.Max = max(summary.Max, v)
+		summary.Mean += v
+	}
+	a.Summaries[descriptor.Name] = summary
+}
+
+// AreaForecast returns the forecast for every site within the area at the time, built from
+// FiveDayForecastForAllLocations and ForecastSiteList. Three hourly forecasts only request the time step covering the
+// time, while daily forecasts request every time step
+func (d *DataPointClient) AreaForecast(resolution Resolution, area Area, at time.Time) (*AreaSnapshot, error) {
+	sites, err := d.ForecastSiteList()
+	if err != nil {
+		return nil, err
+	}
+
+	reps, err := d.FiveDayForecastForAllLocations(resolution, resolution.timeStep(at))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := newAreaSnapshot(d.parameters, reps, sites, area, at)
+	return &snapshot, nil
+}
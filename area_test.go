@@ -0,0 +1,95 @@
+package datapoint_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	dp "github.com/vitineth/datapoint"
+	"github.com/vitineth/datapoint/datapointtest"
+)
+
+func TestAreaSnapshotMixedTypes(t *testing.T) {
+	at := time.Date(2024, time.March, 14, 15, 0, 0, 0, time.UTC)
+	rep := func(id int, ints map[string]int, floats map[string]float64, strings map[string]string) dp.SiteRep {
+		f := dp.Forecast{
+			ValidFrom:    at,
+			ValidTo:      at.Add(3 * time.Hour),
+			IntParams:    map[string]dp.IntParameterValue{},
+			FloatParams:  map[string]dp.FloatParameterValue{},
+			StringParams: map[string]dp.StringParameterValue{},
+		}
+		for k, v := range ints {
+			f.IntParams[k] = dp.IntParameterValue{ParameterDescriptor: dp.ParameterDescriptor{Name: k}, Value: v}
+		}
+		for k, v := range floats {
+			f.FloatParams[k] = dp.FloatParameterValue{ParameterDescriptor: dp.ParameterDescriptor{Name: k}, Value: v}
+		}
+		for k, v := range strings {
+			f.StringParams[k] = dp.StringParameterValue{ParameterDescriptor: dp.ParameterDescriptor{Name: k}, Value: v}
+		}
+		return dp.SiteRep{Location: dp.LocationRep{Id: id, Period: []dp.Period{{Time: at, Forecasts: []dp.Forecast{f}}}}}
+	}
+
+	// each parameter has values of more than one type, in every order, which previously either panicked or left the
+	// running total in Mean
+	reps := []dp.SiteRep{
+		rep(1, map[string]int{"T": 10, "W": 7}, map[string]float64{"Z": 1.5}, map[string]string{"Q": "a"}),
+		rep(2, map[string]int{"Q": 5}, nil, map[string]string{"T": "warm", "W": "7", "Z": "x", "H": "wet"}),
+		rep(3, map[string]int{"W": 12, "Z": 3}, map[string]float64{"T": 14}, map[string]string{"Q": "a"}),
+	}
+	snapshot := dp.NewAreaSnapshot(reps, nil, dp.AreaFunc(func(dp.Site) bool { return true }), at)
+
+	expected := map[string]dp.ParameterSummary{
+		// registered numeric parameter, where only the int is of the registered type
+		"T": {ParameterDescriptor: dp.ParameterDescriptor{Name: "T"}, Count: 1, Skipped: 2, Min: 10, Max: 10, Mean: 10},
+		// registered categorical parameter stored as an int
+		"W": {ParameterDescriptor: dp.ParameterDescriptor{Name: "W"}, Count: 2, Skipped: 1, Counts: map[string]int{"7": 1, "12": 1}},
+		// registered numeric parameter with no value of the registered type
+		"H": {ParameterDescriptor: dp.ParameterDescriptor{Name: "H"}, Count: 0, Skipped: 1},
+		// unregistered parameters take their kind from the first value
+		"Z": {ParameterDescriptor: dp.ParameterDescriptor{Name: "Z"}, Count: 2, Skipped: 1, Min: 1.5, Max: 3, Mean: 2.25},
+		"Q": {ParameterDescriptor: dp.ParameterDescriptor{Name: "Q"}, Count: 2, Skipped: 1, Counts: map[string]int{"a": 2}},
+	}
+	if !reflect.DeepEqual(snapshot.Summaries, expected) {
+		t.Errorf("expected summaries\n%+v\nbut got\n%+v", expected, snapshot.Summaries)
+	}
+	if !reflect.DeepEqual(snapshot.Missing, []int{1, 2, 3}) {
+		t.Errorf("expected every site to be missing from the site list but got %v", snapshot.Missing)
+	}
+}
+
+func TestAreaForecast(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	sites := datapointtest.DefaultForecastSites()
+	snapshot, err := client.AreaForecast(dp.ResolutionThreeHourly, dp.BoundingBox{South: 49, West: -11, North: 61, East: 2}, datapointtest.DefaultDataDate)
+	if err != nil {
+		t.Fatalf("failed to fetch area forecast: %v", err)
+	}
+	if len(snapshot.Sites) != len(sites) || len(snapshot.Missing) != 0 {
+		t.Fatalf("expected all %v sites with none missing but got %v sites and missing %v", len(sites), len(snapshot.Sites), snapshot.Missing)
+	}
+
+	for name, categorical := range map[string]bool{"T": false, "H": false, "W": true, "V": true, "D": true} {
+		summary, ok := snapshot.Summaries[name]
+		if !ok {
+			t.Errorf("expected a summary of %v", name)
+			continue
+		}
+		if summary.Count != len(sites) || summary.Skipped != 0 {
+			t.Errorf("expected %v to have a value from every site but got %v with %v skipped", name, summary.Count, summary.Skipped)
+		}
+		if (summary.Counts != nil) != categorical {
+			t.Errorf("expected %v to be categorical %v but got counts %v", name, categorical, summary.Counts)
+		}
+		if !categorical && (summary.Mean < summary.Min || summary.Mean > summary.Max) {
+			t.Errorf("expected the mean of %v to lie between %v and %v but got %v", name, summary.Min, summary.Max, summary.Mean)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package datapoint
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// CompassPoint is one of the 16 points of the compass which DataPoint uses to report the direction the wind is blowing
+// from, along with the special cases of calm and variable winds which have no direction
+type CompassPoint int
+
+const (
+	CompassCalm     CompassPoint = -2
+	CompassVariable CompassPoint = -1
+)
+
+const (
+	CompassNorth CompassPoint = iota
+	CompassNorthNorthEast
+	CompassNorthEast
+	CompassEastNorthEast
+	CompassEast
+	CompassEastSouthEast
+	CompassSouthEast
+	CompassSouthSouthEast
+	CompassSouth
+	CompassSouthSouthWest
+	CompassSouthWest
+	CompassWestSouthWest
+	CompassWest
+	CompassWestNorthWest
+	CompassNorthWest
+	CompassNorthNorthWest
+)
+
+const (
+	compassPoints    = 16
+	compassPointSize = 360.0 / compassPoints
+
+	compassCalmCode     = "CALM"
+	compassVariableCode = "VRB"
+)
+
+// ParseCompassPoint converts a direction such as 'SSW' into a CompassPoint. The values 'CALM' and 'C' are parsed as
+// CompassCalm, and 'VRB', 'VAR' and 'VARIABLE' as CompassVariable. Parsing is not case-sensitive
+func ParseCompassPoint(value string) (CompassPoint, error) {
+	code := strings.ToUpper(strings.TrimSpace(value))
+	switch code {
+	case compassCalmCode, "C":
+		return CompassCalm, nil
+	case compassVariableCode, "VAR", "VARIABLE":
+		return CompassVariable, nil
+	}
+
+	for i, name := range compassValues {
+		if name == code {
+			return CompassPoint(i), nil
+		}
+	}
+	return CompassVariable, fmt.Errorf("unknown compass direction %v", value)
+}
+
+// CompassPointFromDegrees returns the compass point nearest to a bearing in degrees clockwise from north
+func CompassPointFromDegrees(degrees float64) CompassPoint {
+	index := int(math.Round(normaliseDegrees(degrees)/compassPointSize)) % compassPoints
+	return CompassPoint(index)
+}
+
+// IsDirectional returns if the point has a direction, which is false for calm and variable winds
+func (c CompassPoint) IsDirectional() bool {
+	return c >= CompassNorth && c <= CompassNorthNorthWest
+}
+
+// String returns the abbreviation of the point as used by DataPoint, e.g. 'SSW'
+func (c CompassPoint) String() string {
+	switch {
+	case c == CompassCalm:
+		return compassCalmCode
+	case c == CompassVariable:
+		return compassVariableCode
+	case c.IsDirectional():
+		return compassValues[c]
+	default:
+		return fmt.Sprintf("CompassPoint(%d)", int(c))
+	}
+}
+
+// Localise returns the name of the compass point in the language, e.g. 'South south west' or 'De-de-orllewin'
+func (c CompassPoint) Localise(language Language) string {
+	return LocaliseCompass(c.String(), language)
+}
+
+// Degrees returns the bearing of the point in degrees clockwise from north. The second value is false for calm and
+// variable winds, which have no bearing
+func (c CompassPoint) Degrees() (float64, bool) {
+	if !c.IsDirectional() {
+		return 0, false
+	}
+	return float64(c) * compassPointSize, true
+}
+
+// Radians returns the bearing of the point in radians clockwise from north
+func (c CompassPoint) Radians() (float64, bool) {
+	d, ok := c.Degrees()
+	return d * math.Pi / 180, ok
+}
+
+// Difference returns the signed angle in degrees to turn clockwise from this point to the other, in the range
+// (-180, 180]. The second value is false if either point has no bearing
+func (c CompassPoint) Difference(other CompassPoint) (float64, bool) {
+	a, ok := c.Degrees()
+	if !ok {
+		return 0, false
+	}
+	b, ok := other.Degrees()
+	if !ok {
+		return 0, false
+	}
+	return AngularDifference(a, b), true
+}
+
+// Components returns the eastward (u) and northward (v) components of a wind of the given speed blowing from this
+// point, using the meteorological convention that a wind from the north has a negative v component. Calm and variable
+// winds return zero for both components with false
+func (c CompassPoint) Components(speed float64) (float64, float64, bool) {
+	if !c.IsDirectional() {
+		return 0, 0, false
+	}
+	d, _ := c.Degrees()
+	u, v := WindComponents(speed, d)
+	return u, v, true
+}
+
+// normaliseDegrees wraps a bearing into the range [0, 360)
+func normaliseDegrees(degrees float64) float64 {
+	d := math.Mod(degrees, 360)
+	if d < 0 {
+		d += 360
+	}
+	return d
+}
+
+// AngularDifference returns the signed angle in degrees to turn clockwise from bearing a to bearing b, in the range
+// (-180, 180]
+func AngularDifference(a float64, b float64) float64 {
+	d := normaliseDegrees(b - a)
+	if d > 180 {
+		d -= 360
+	}
+	return d
+}
+
+// WindComponents returns the eastward (u) and northward (v) components of a wind of the given speed blowing from the
+// bearing in degrees
+func WindComponents(speed float64, fromDegrees float64) (float64, float64) {
+	r := fromDegrees * math.Pi / 180
+	return -speed * math.Sin(r), -speed * math.Cos(r)
+}
+
+// WindFromComponents is the inverse of WindComponents, returning the speed and the bearing in degrees the wind is
+// blowing from. This can be used to average directions by summing their components. A zero vector returns a speed and
+// bearing of zero
+func WindFromComponents(u float64, v float64) (float64, float64) {
+	speed := math.Hypot(u, v)
+	if speed == 0 {
+		return 0, 0
+	}
+	return speed, normaliseDegrees(math.Atan2(-u, -v) * 180 / math.Pi)
+}
+
+// WindCompass returns the direction the wind is blowing from as a CompassPoint
+func (f Forecast) WindCompass() (CompassPoint, bool) {
+	v, ok := f.WindDirection()
+	if !ok {
+		return CompassVariable, false
+	}
+	c, err := ParseCompassPoint(v.Value)
+	if err != nil {
+		return CompassVariable, false
+	}
+	return c, true
+}
@@ -1,5 +1,7 @@
 package datapoint
 
+import "time"
+
 type Resolution string
 
 const (
@@ -7,32 +9,56 @@ const (
 	ResolutionDaily       Resolution = "daily"
 )
 
+// step returns the time between forecasts of this resolution, or 0 if forecasts are not evenly spaced
+func (r Resolution) step() time.Duration {
+	switch r {
+	case ResolutionThreeHourly:
+		return 3 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// timeStep returns the time step to request so a response only includes the forecast covering the time, or nil if
+// forecasts of this resolution must all be requested
+func (r Resolution) timeStep(at time.Time) *time.Time {
+	if r.step() == 0 {
+		return nil
+	}
+	t := at.UTC().Truncate(r.step())
+	return &t
+}
+
 type KnownParameter string
 
 const (
-	KnownParameterFeelsLikeTemp                  KnownParameter = "F"
-	KnownParameterWindGust                       KnownParameter = "G"
-	KnownParameterWindGustNoon                   KnownParameter = "Gn"
-	KnownParameterScreenRelativeHumidity         KnownParameter = "H"
-	KnownParameterScreenRelativeHumidityNoon     KnownParameter = "Hn"
-	KnownParameterTemperature                    KnownParameter = "T"
-	KnownParameterDayMaximumTemperature          KnownParameter = "Dm"
-	KnownParameterNightMinimumTemperature        KnownParameter = "Nm"
-	KnownParameterFeelsLikeDayMaximumTemperature KnownParameter = "FDm"
-	KnownParameterVisibility                     KnownParameter = "V"
-	KnownParameterWindDirection                  KnownParameter = "D"
-	KnownParameterWindSpeed                      KnownParameter = "S"
-	KnownParameterMaxUvIndex                     KnownParameter = "U"
-	KnownParameterWeatherType                    KnownParameter = "W"
-	KnownParameterPrecipitationProbability       KnownParameter = "Pp"
-	KnownParameterPrecipitationProbabilityDay    KnownParameter = "PPd"
-	KnownParameterPrecipitationProbabilityNight  KnownParameter = "PPn"
+	KnownParameterFeelsLikeTemp                    KnownParameter = "F"
+	KnownParameterWindGust                         KnownParameter = "G"
+	KnownParameterWindGustNoon                     KnownParameter = "Gn"
+	KnownParameterWindGustMidnight                 KnownParameter = "Gm"
+	KnownParameterScreenRelativeHumidity           KnownParameter = "H"
+	KnownParameterScreenRelativeHumidityNoon       KnownParameter = "Hn"
+	KnownParameterScreenRelativeHumidityMidnight   KnownParameter = "Hm"
+	KnownParameterTemperature                      KnownParameter = "T"
+	KnownParameterDayMaximumTemperature            KnownParameter = "Dm"
+	KnownParameterNightMinimumTemperature          KnownParameter = "Nm"
+	KnownParameterFeelsLikeDayMaximumTemperature   KnownParameter = "FDm"
+	KnownParameterFeelsLikeNightMinimumTemperature KnownParameter = "FNm"
+	KnownParameterVisibility                       KnownParameter = "V"
+	KnownParameterWindDirection                    KnownParameter = "D"
+	KnownParameterWindSpeed                        KnownParameter = "S"
+	KnownParameterMaxUvIndex                       KnownParameter = "U"
+	KnownParameterWeatherType                      KnownParameter = "W"
+	KnownParameterPrecipitationProbability         KnownParameter = "Pp"
+	KnownParameterPrecipitationProbabilityDay      KnownParameter = "PPd"
+	KnownParameterPrecipitationProbabilityNight    KnownParameter = "PPn"
+	KnownParameterDewPoint                         KnownParameter = "Dp"
 )
 
 type UvIndex int
 
 func (i UvIndex) IsLowExposure() bool {
-	return i >= 1 && i <= 2
+	return i >= 0 && i <= 2
 }
 
 func (i UvIndex) IsModerateExposure() bool {
@@ -54,10 +80,12 @@ func (i UvIndex) IsExtremeExposure() bool {
 type WeatherType int
 
 const (
+	WeatherTypeNotAvailable         WeatherType = -1
 	WeatherTypeClearNight           WeatherType = 0
 	WeatherTypeSunnyDay             WeatherType = 1
 	WeatherTypePartlyCloudyNight    WeatherType = 2
 	WeatherTypePartlyCloudyDay      WeatherType = 3
+	WeatherTypeNotUsed              WeatherType = 4
 	WeatherTypeMist                 WeatherType = 5
 	WeatherTypeFog                  WeatherType = 6
 	WeatherTypeCloudy               WeatherType = 7
@@ -0,0 +1,107 @@
+package datapoint
+
+import (
+	"time"
+	_ "time/tzdata"
+)
+
+// London is the Europe/London time zone which the Met Office uses to define days and nights
+var London = mustLoadLocation("Europe/London")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// Segment is the part of a day which a daily resolution forecast covers
+type Segment string
+
+const (
+	// SegmentNone is used for forecasts which are not split into day and night, such as three hourly forecasts
+	SegmentNone Segment = ""
+	// SegmentDay covers 06:00 to 18:00 local time
+	SegmentDay Segment = "Day"
+	// SegmentNight covers 18:00 local time to 06:00 local time on the following day
+	SegmentNight Segment = "Night"
+)
+
+const (
+	dayStartHour   = 6
+	nightStartHour = 18
+)
+
+// segmentParameters holds the parameters of a daily forecast which are only returned for one of the segments
+var segmentParameters = map[string]Segment{
+	string(KnownParameterDayMaximumTemperature):            SegmentDay,
+	string(KnownParameterFeelsLikeDayMaximumTemperature):   SegmentDay,
+	string(KnownParameterWindGustNoon):                     SegmentDay,
+	string(KnownParameterScreenRelativeHumidityNoon):       SegmentDay,
+	string(KnownParameterPrecipitationProbabilityDay):      SegmentDay,
+	string(KnownParameterMaxUvIndex):                       SegmentDay,
+	string(KnownParameterNightMinimumTemperature):          SegmentNight,
+	string(KnownParameterFeelsLikeNightMinimumTemperature): SegmentNight,
+	string(KnownParameterWindGustMidnight):                 SegmentNight,
+	string(KnownParameterScreenRelativeHumidityMidnight):   SegmentNight,
+	string(KnownParameterPrecipitationProbabilityNight):    SegmentNight,
+}
+
+// includes returns if a forecast for the segment is expected to contain the parameter
+func (s Segment) includes(param string) bool {
+	if s == SegmentNone {
+		return true
+	}
+	only, ok := segmentParameters[param]
+	return !ok || only == s
+}
+
+// Window returns the period during which a forecast for this segment on the given date is valid. The date is
+// interpreted as a calendar date in Europe/London, so the window follows the clocks changing. SegmentNone has no window
+// and returns the zero time for both values
+func (s Segment) Window(date time.Time) (time.Time, time.Time) {
+	y, m, d := date.Date()
+	switch s {
+	case SegmentDay:
+		return time.Date(y, m, d, dayStartHour, 0, 0, 0, London).UTC(), time.Date(y, m, d, nightStartHour, 0, 0, 0, London).UTC()
+	case SegmentNight:
+		return time.Date(y, m, d, nightStartHour, 0, 0, 0, London).UTC(), time.Date(y, m, d+1, dayStartHour, 0, 0, 0, London).UTC()
+	default:
+		return time.Time{}, time.Time{}
+	}
+}
+
+// DailyForecast pairs the day and night segments of a single date from a daily resolution forecast
+type DailyForecast struct {
+	// Date is midnight at the start of the date in Europe/London
+	Date time.Time
+	// Day is the forecast for the day segment, this may be nil if the forecast was issued after the day had ended
+	Day *Forecast
+	// Night is the forecast for the night segment, this may be nil if the forecast does not extend that far
+	Night *Forecast
+}
+
+// DailyForecasts groups the forecasts of a daily resolution SiteRep into one entry per date. Forecasts which are not
+// part of a day or night segment are ignored, so this returns nil for three hourly forecasts
+func (s SiteRep) DailyForecasts() []DailyForecast {
+	var days []DailyForecast
+	for _, period := range s.Location.Period {
+		y, m, d := period.Time.Date()
+		day := DailyForecast{Date: time.Date(y, m, d, 0, 0, 0, 0, London)}
+		for i := range period.Forecasts {
+			f := &period.Forecasts[i]
+			switch f.Segment {
+			case SegmentDay:
+				day.Day = f
+			case SegmentNight:
+				day.Night = f
+			}
+		}
+
+		if day.Day != nil || day.Night != nil {
+			days = append(days, day)
+		}
+	}
+	return days
+}
@@ -20,6 +20,9 @@ type DataPointClient struct {
 	apiKeySupplier *Supplier[string]
 	baseUrl        string
 	httpClient     *http.Client
+	decodingMode   DecodingMode
+	parameters     *ParameterRegistry
+	siteLists      map[string]*siteListCache
 }
 
 // Opt is an option that can apply to a DataPointClient
@@ -96,6 +99,7 @@ func NewClient(opt ...Opt) (*DataPointClient, error) {
 	client := DataPointClient{
 		baseUrl:    "http://datapoint.metoffice.gov.uk/public/data/",
 		httpClient: &http.Client{},
+		parameters: NewParameterRegistry(),
 	}
 
 	for _, o := range opt {
@@ -105,11 +109,46 @@ func NewClient(opt ...Opt) (*DataPointClient, error) {
 	if client.apiKeySupplier == nil {
 		return nil, errors.New("no api key provided")
 	}
+	if client.parameters == nil {
+		return nil, errors.New("no parameter registry provided")
+	}
 
 	return &client, nil
 }
 
+// StatusError is returned when the service responds with a status other than 2xx, such as 403 when the API key is
+// invalid or its quota has been used up
+type StatusError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Target is the URL which was queried, without the API key
+	Target string
+	// Body is the body of the response, which usually describes the problem
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%v responded with status %v: %v", e.Target, e.StatusCode, e.Body)
+}
+
 func (d *DataPointClient) fetch(description string, suffix string, params map[string]string) ([]byte, string, error) {
+	r, target, err := d.open(description, suffix, params)
+	if err != nil {
+		return nil, target, err
+	}
+	defer closeBody(r, description)
+
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, target, fmt.Errorf("failed to read body from response from %v for %v: %w", target, description, err)
+	}
+
+	return body, target, nil
+}
+
+// open queries the service and returns the body of the response without reading it, which must be closed by the
+// caller. Responses with a status other than 2xx are returned as a StatusError
+func (d *DataPointClient) open(description string, suffix string, params map[string]string) (io.ReadCloser, string, error) {
 	target, err := url.JoinPath(d.baseUrl, suffix)
 	if err != nil {
 		return nil, "???", fmt.Errorf("failed to generate %v url: %w", description, err)
@@ -127,17 +166,25 @@ func (d *DataPointClient) fetch(description string, suffix string, params map[st
 		return nil, target, fmt.Errorf("failed to query %v for %v: %w", target, description, err)
 	}
 
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		defer closeBody(r.Body, description)
+		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			slog.Warn("failed to close body from query", "err", err, "desc", description)
+			return nil, target, fmt.Errorf("failed to read body from response from %v for %v: %w", target, description, err)
 		}
-	}(r.Body)
+		return nil, target, fmt.Errorf("failed to query %v: %w", description, &StatusError{
+			StatusCode: r.StatusCode,
+			Target:     target,
+			Body:       string(body),
+		})
+	}
 
-	body, err := io.ReadAll(r.Body)
+	return r.Body, target, nil
+}
+
+func closeBody(body io.ReadCloser, description string) {
+	err := body.Close()
 	if err != nil {
-		return nil, target, fmt.Errorf("failed to read body from response from %v for %v: %w", target, description, err)
+		slog.Warn("failed to close body from query", "err", err, "desc", description)
 	}
-
-	return body, target, nil
 }
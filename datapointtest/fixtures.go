@@ -0,0 +1,210 @@
+package datapointtest
+
+import (
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	dp "github.com/vitineth/datapoint"
+)
+
+// DefaultDataDate is the time at which forecasts served by a default Server claim to have been run
+var DefaultDataDate = time.Date(2024, time.March, 14, 15, 0, 0, 0, time.UTC)
+
+// DefaultForecastSites returns the sites for which a default Server provides forecasts. This includes several sites
+// sharing the name Newport to mirror the real site list
+func DefaultForecastSites() []dp.Site {
+	return []dp.Site{
+		{Id: 310069, Latitude: 50.7179, Longitude: -3.5327, Name: "Exeter", Elevation: 27, Region: "sw", UnitaryAuthArea: "Devon"},
+		{Id: 310016, Latitude: 50.3714, Longitude: -4.1422, Name: "Plymouth", Elevation: 50, Region: "sw", UnitaryAuthArea: "Plymouth"},
+		{Id: 352409, Latitude: 51.5081, Longitude: -0.1248, Name: "London", Elevation: 11, Region: "se", UnitaryAuthArea: "Greater London"},
+		{Id: 350758, Latitude: 51.4816, Longitude: -3.1791, Name: "Cardiff", Elevation: 13, Region: "wl", UnitaryAuthArea: "Cardiff"},
+		{Id: 350347, Latitude: 51.5842, Longitude: -2.9977, Name: "Newport", Elevation: 12, Region: "wl", UnitaryAuthArea: "Newport"},
+		{Id: 354160, Latitude: 50.7010, Longitude: -1.2883, Name: "Newport", Elevation: 18, Region: "se", UnitaryAuthArea: "Isle of Wight"},
+		{Id: 324152, Latitude: 52.7691, Longitude: -2.3787, Name: "Newport", Elevation: 66, Region: "wm", UnitaryAuthArea: "Telford and Wrekin"},
+		{Id: 351351, Latitude: 55.9533, Longitude: -3.1883, Name: "Edinburgh", Elevation: 47, Region: "dg", UnitaryAuthArea: "City of Edinburgh"},
+		{Id: 350929, Latitude: 54.5973, Longitude: -5.9301, Name: "Belfast", Elevation: 5, Region: "ni", UnitaryAuthArea: "Belfast"},
+		{Id: 3066, Latitude: 57.6494, Longitude: -3.5606, Name: "Kinloss", Elevation: 5, Region: "gr", UnitaryAuthArea: "Moray"},
+	}
+}
+
+// DefaultObservationSites returns the sites listed by the observation site list of a default Server
+func DefaultObservationSites() []dp.Site {
+	return []dp.Site{
+		{Id: 3772, Latitude: 51.479, Longitude: -0.449, Name: "Heathrow", Elevation: 25, Region: "se", UnitaryAuthArea: "Greater London"},
+		{Id: 3844, Latitude: 50.737, Longitude: -3.405, Name: "Exeter Airport", Elevation: 27, Region: "sw", UnitaryAuthArea: "Devon"},
+		{Id: 3066, Latitude: 57.6494, Longitude: -3.5606, Name: "Kinloss", Elevation: 5, Region: "gr", UnitaryAuthArea: "Moray"},
+		{Id: 3917, Latitude: 54.664, Longitude: -6.224, Name: "Belfast International Airport", Elevation: 63, Region: "ni", UnitaryAuthArea: "Antrim"},
+	}
+}
+
+// DefaultRegionalSites returns the regions listed by the regional forecast site list of a default Server
+func DefaultRegionalSites() []dp.RegionalForecastSite {
+	return []dp.RegionalForecastSite{
+		{Id: 500, Name: "os"}, {Id: 501, Name: "he"}, {Id: 502, Name: "wh"}, {Id: 503, Name: "gr"},
+		{Id: 504, Name: "ta"}, {Id: 505, Name: "st"}, {Id: 506, Name: "dg"}, {Id: 507, Name: "ni"},
+		{Id: 508, Name: "yh"}, {Id: 509, Name: "ne"}, {Id: 510, Name: "em"}, {Id: 511, Name: "ee"},
+		{Id: 512, Name: "se"}, {Id: 513, Name: "nw"}, {Id: 514, Name: "wm"}, {Id: 515, Name: "sw"},
+		{Id: 516, Name: "wl"}, {Id: 517, Name: "uk"},
+	}
+}
+
+// DefaultExtremes returns the extremes served by a default Server, observed on the day before the data date
+func DefaultExtremes(dataDate time.Time) dp.LatestExtremes {
+	day := dataDate.UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	return dp.LatestExtremes{
+		ExtremeDate: day,
+		IssuedAt:    day.Add(33 * time.Hour),
+		Regions: []dp.Region{
+			{Id: "uk", Name: "UK", Extremes: []dp.Extreme{
+				{LocationId: 3772, LocationName: "Heathrow", Type: "HMAXT", UnitOfMeasurement: "degC", Value: 14.2},
+				{LocationId: 3066, LocationName: "Kinloss", Type: "LMINT", UnitOfMeasurement: "degC", Value: -3.1},
+				{LocationId: 3066, LocationName: "Kinloss", Type: "LMAXT", UnitOfMeasurement: "degC", Value: 4.8},
+				{LocationId: 3844, LocationName: "Exeter Airport", Type: "HRAIN", UnitOfMeasurement: "mm", Value: 12.6},
+				{LocationId: 3772, LocationName: "Heathrow", Type: "HSUN", UnitOfMeasurement: "hours", Value: 7.4},
+			}},
+			{Id: "ni", Name: "Northern Ireland", Extremes: []dp.Extreme{
+				{LocationId: 3917, LocationName: "Belfast International Airport", Type: "HMAXT", UnitOfMeasurement: "degC", Value: 11.0},
+				{LocationId: 3917, LocationName: "Belfast International Airport", Type: "LMINT", UnitOfMeasurement: "degC", Value: 1.2},
+			}},
+		},
+	}
+}
+
+type paramDefinition struct {
+	name        string
+	units       string
+	description string
+}
+
+var (
+	threeHourlyParams = []paramDefinition{
+		{"F", "C", "Feels Like Temperature"},
+		{"G", "mph", "Wind Gust"},
+		{"H", "%", "Screen Relative Humidity"},
+		{"T", "C", "Temperature"},
+		{"V", "", "Visibility"},
+		{"D", "compass", "Wind Direction"},
+		{"S", "mph", "Wind Speed"},
+		{"U", "", "Max UV Index"},
+		{"W", "", "Weather Type"},
+		{"Pp", "%", "Precipitation Probability"},
+	}
+	dailyParams = []paramDefinition{
+		{"FDm", "C", "Feels Like Day Maximum Temperature"},
+		{"FNm", "C", "Feels Like Night Minimum Temperature"},
+		{"Dm", "C", "Day Maximum Temperature"},
+		{"Nm", "C", "Night Minimum Temperature"},
+		{"Gn", "mph", "Wind Gust Noon"},
+		{"Gm", "mph", "Wind Gust Midnight"},
+		{"Hn", "%", "Screen Relative Humidity Noon"},
+		{"Hm", "%", "Screen Relative Humidity Midnight"},
+		{"V", "", "Visibility"},
+		{"D", "compass", "Wind Direction"},
+		{"S", "mph", "Wind Speed"},
+		{"U", "", "Max UV Index"},
+		{"W", "", "Weather Type"},
+		{"PPd", "%", "Precipitation Probability Day"},
+		{"PPn", "%", "Precipitation Probability Night"},
+	}
+
+	compassPoints  = []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+	visibilities   = []string{"VP", "PO", "MO", "GO", "VG", "EX"}
+	dayWeathers    = []int{1, 3, 7, 8, 10, 12, 14, 15, 23}
+	nightWeathers  = []int{0, 2, 5, 7, 8, 9, 12, 13, 22}
+	forecastDays   = 5
+	threeHourStep  = 3 * time.Hour
+	dailyNightTime = 12 * time.Hour
+)
+
+// noise returns a deterministic pseudo-random value in [0, n) for the given site, time and parameter
+func noise(site int, at time.Time, param string, n int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strconv.Itoa(site) + "/" + at.Format(time.RFC3339) + "/" + param))
+	return int(h.Sum32() % uint32(n))
+}
+
+// timeSteps returns every time step which will be served for the resolution. For daily resolution the day step is
+// represented at midnight and the night step at midday, as they are in the capabilities feed
+func (s *Server) timeSteps(resolution dp.Resolution) []time.Time {
+	start := s.dataDate.Truncate(24 * time.Hour)
+	var steps []time.Time
+	if resolution == dp.ResolutionDaily {
+		for day := 0; day < forecastDays; day++ {
+			date := start.AddDate(0, 0, day)
+			steps = append(steps, date, date.Add(dailyNightTime))
+		}
+		return steps
+	}
+
+	first := s.dataDate.Truncate(threeHourStep)
+	end := start.AddDate(0, 0, forecastDays)
+	for t := first; t.Before(end); t = t.Add(threeHourStep) {
+		steps = append(steps, t)
+	}
+	return steps
+}
+
+func threeHourlyRep(site dp.Site, at time.Time) map[string]any {
+	hour := at.Hour()
+	// a rough diurnal curve peaking in the mid afternoon with a latitude adjustment
+	diurnal := []int{-3, -4, -1, 3, 5, 4, 1, -1}[hour/3]
+	temperature := 12 - int(site.Latitude-50) + diurnal + noise(site.Id, at, "T", 5) - 2
+	wind := 4 + noise(site.Id, at, "S", 16)
+
+	weathers := nightWeathers
+	uv := 0
+	if hour >= 6 && hour < 18 {
+		weathers = dayWeathers
+		uv = 1 + noise(site.Id, at, "U", 4)
+	}
+
+	return map[string]any{
+		"$":  formatInt(hour * 60),
+		"F":  formatInt(temperature - wind/6),
+		"G":  formatInt(wind + 4 + noise(site.Id, at, "G", 12)),
+		"H":  formatInt(55 + noise(site.Id, at, "H", 45)),
+		"T":  formatInt(temperature),
+		"V":  visibilities[noise(site.Id, at, "V", len(visibilities))],
+		"D":  compassPoints[noise(site.Id, at, "D", len(compassPoints))],
+		"S":  formatInt(wind),
+		"U":  formatInt(uv),
+		"W":  formatInt(weathers[noise(site.Id, at, "W", len(weathers))]),
+		"Pp": formatInt(noise(site.Id, at, "Pp", 100)),
+	}
+}
+
+func dailyReps(site dp.Site, date time.Time, night bool) map[string]any {
+	maximum := 13 - int(site.Latitude-50) + noise(site.Id, date, "Dm", 5)
+	minimum := maximum - 6 - noise(site.Id, date, "Nm", 4)
+	if night {
+		wind := 3 + noise(site.Id, date, "Sn", 12)
+		return map[string]any{
+			"$":   "Night",
+			"FNm": formatInt(minimum - wind/6),
+			"Nm":  formatInt(minimum),
+			"Gm":  formatInt(wind + 5 + noise(site.Id, date, "Gm", 10)),
+			"Hm":  formatInt(70 + noise(site.Id, date, "Hm", 30)),
+			"V":   visibilities[noise(site.Id, date, "Vn", len(visibilities))],
+			"D":   compassPoints[noise(site.Id, date, "Dn", len(compassPoints))],
+			"S":   formatInt(wind),
+			"W":   formatInt(nightWeathers[noise(site.Id, date, "Wn", len(nightWeathers))]),
+			"PPn": formatInt(noise(site.Id, date, "PPn", 100)),
+		}
+	}
+
+	wind := 4 + noise(site.Id, date, "Sd", 16)
+	return map[string]any{
+		"$":   "Day",
+		"FDm": formatInt(maximum - wind/6),
+		"Dm":  formatInt(maximum),
+		"Gn":  formatInt(wind + 5 + noise(site.Id, date, "Gn", 12)),
+		"Hn":  formatInt(50 + noise(site.Id, date, "Hn", 40)),
+		"V":   visibilities[noise(site.Id, date, "Vd", len(visibilities))],
+		"D":   compassPoints[noise(site.Id, date, "Dd", len(compassPoints))],
+		"S":   formatInt(wind),
+		"U":   formatInt(1 + noise(site.Id, date, "U", 5)),
+		"W":   formatInt(dayWeathers[noise(site.Id, date, "Wd", len(dayWeathers))]),
+		"PPd": formatInt(noise(site.Id, date, "PPd", 100)),
+	}
+}
@@ -0,0 +1,218 @@
+package datapointtest
+
+import (
+	"strconv"
+	"time"
+
+	dp "github.com/vitineth/datapoint"
+)
+
+func formatInt(v int) string {
+	return strconv.Itoa(v)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func siteListBody(sites []dp.Site) any {
+	locations := make([]any, len(sites))
+	for i, site := range sites {
+		locations[i] = map[string]any{
+			"elevation":       strconv.FormatFloat(site.Elevation, 'f', 1, 64),
+			"id":              formatInt(site.Id),
+			"latitude":        formatFloat(site.Latitude),
+			"longitude":       formatFloat(site.Longitude),
+			"name":            site.Name,
+			"region":          site.Region,
+			"unitaryAuthArea": site.UnitaryAuthArea,
+		}
+	}
+
+	return map[string]any{
+		"Locations": map[string]any{
+			"Location": locations,
+		},
+	}
+}
+
+func regionalSiteListBody(sites []dp.RegionalForecastSite) any {
+	locations := make([]any, len(sites))
+	for i, site := range sites {
+		locations[i] = map[string]any{
+			"@id":   formatInt(site.Id),
+			"@name": site.Name,
+		}
+	}
+
+	return map[string]any{
+		"Locations": map[string]any{
+			"Location": locations,
+		},
+	}
+}
+
+func (s *Server) capabilitiesBody(resolution dp.Resolution) any {
+	steps := s.timeSteps(resolution)
+	ts := make([]any, len(steps))
+	for i, step := range steps {
+		ts[i] = step.Format(time.RFC3339)
+	}
+
+	return map[string]any{
+		"Resource": map[string]any{
+			"dataDate": s.dataDate.Format(time.RFC3339),
+			"res":      string(resolution),
+			"type":     "wxfcs",
+			"TimeSteps": map[string]any{
+				"TS": ts,
+			},
+		},
+	}
+}
+
+func (s *Server) forecastBody(resolution dp.Resolution, at string, sites []dp.Site, multiple bool) any {
+	definitions := threeHourlyParams
+	if resolution == dp.ResolutionDaily {
+		definitions = dailyParams
+	}
+
+	params := make([]any, len(definitions))
+	for i, p := range definitions {
+		params[i] = map[string]any{
+			"name":  p.name,
+			"units": p.units,
+			"$":     p.description,
+		}
+	}
+
+	var only *time.Time
+	if at != "" {
+		if t, err := time.Parse(time.RFC3339, at); err == nil {
+			only = &t
+		}
+	}
+
+	locations := make([]any, len(sites))
+	for i, site := range sites {
+		locations[i] = s.locationBody(resolution, site, only)
+	}
+
+	dv := map[string]any{
+		"dataDate": s.dataDate.Format(time.RFC3339),
+		"type":     "Forecast",
+	}
+	if multiple {
+		dv["Location"] = locations
+	} else if len(locations) == 1 {
+		dv["Location"] = locations[0]
+	}
+
+	return map[string]any{
+		"SiteRep": map[string]any{
+			"Wx": map[string]any{
+				"Param": params,
+			},
+			"DV": dv,
+		},
+	}
+}
+
+func (s *Server) locationBody(resolution dp.Resolution, site dp.Site, only *time.Time) any {
+	var periods []any
+	var current []any
+	var currentDate time.Time
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		periods = append(periods, map[string]any{
+			"type":  "Day",
+			"value": currentDate.Format("2006-01-02Z"),
+			"Rep":   current,
+		})
+		current = nil
+	}
+
+	for _, step := range s.timeSteps(resolution) {
+		if only != nil && !only.Equal(step) {
+			continue
+		}
+
+		date := step.Truncate(24 * time.Hour)
+		if !date.Equal(currentDate) {
+			flush()
+			currentDate = date
+		}
+
+		if resolution == dp.ResolutionDaily {
+			current = append(current, dailyReps(site, date, !step.Equal(date)))
+		} else {
+			current = append(current, threeHourlyRep(site, step))
+		}
+	}
+	flush()
+
+	return map[string]any{
+		"i":         formatInt(site.Id),
+		"lat":       formatFloat(site.Latitude),
+		"lon":       formatFloat(site.Longitude),
+		"name":      site.Name,
+		"country":   "UNITED KINGDOM",
+		"continent": "EUROPE",
+		"elevation": strconv.FormatFloat(site.Elevation, 'f', 1, 64),
+		"Period":    periods,
+	}
+}
+
+func (s *Server) extremesCapabilitiesBody() any {
+	return map[string]any{
+		"UkExtremes": map[string]any{
+			"extremeDate": s.extremes.ExtremeDate.Format(time.DateOnly),
+			"issuedAt":    s.extremes.IssuedAt.Format(time.RFC3339),
+		},
+	}
+}
+
+func (s *Server) extremesBody() any {
+	regions := make([]any, len(s.extremes.Regions))
+	for i, region := range s.extremes.Regions {
+		extremes := make([]any, len(region.Extremes))
+		for j, extreme := range region.Extremes {
+			extremes[j] = map[string]any{
+				"locId":        formatInt(extreme.LocationId),
+				"locationName": extreme.LocationName,
+				"type":         string(extreme.Type),
+				"uom":          extreme.UnitOfMeasurement,
+				"$":            formatFloat(extreme.Value),
+			}
+		}
+
+		regions[i] = map[string]any{
+			"id":   region.Id,
+			"name": region.Name,
+			"Extremes": map[string]any{
+				"Extreme": extremes,
+			},
+		}
+	}
+
+	return map[string]any{
+		"UkExtremes": map[string]any{
+			"extremeDate": s.extremes.ExtremeDate.Format(time.DateOnly),
+			"issuedAt":    s.extremes.IssuedAt.Format(time.RFC3339),
+			"Regions": map[string]any{
+				"Region": regions,
+			},
+		},
+	}
+}
+
+func (s *Server) regionalCapabilitiesBody() any {
+	return map[string]any{
+		"RegionalFcst": map[string]any{
+			"issuedAt": s.dataDate.Truncate(time.Hour).Format(time.RFC3339),
+		},
+	}
+}
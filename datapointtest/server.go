@@ -0,0 +1,329 @@
+/*
+Package datapointtest provides a local stand-in for the Met Office DataPoint service which can be used in tests. The
+server is built on httptest and serves responses shaped like the real API for every endpoint the datapoint client
+supports, so that tests exercise the real decoding paths rather than mocked results.
+
+	server := datapointtest.NewServer()
+	defer server.Close()
+
+	client, err := server.Client()
+	if err != nil {
+		panic(err)
+	}
+
+	list, err := client.ForecastSiteList()
+
+The server can be scripted to inject errors, latency, quota exhaustion and the single-object JSON quirk of the real
+service where arrays containing a single element are returned as a bare object.
+*/
+package datapointtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	dp "github.com/vitineth/datapoint"
+)
+
+// DefaultApiKey is the API key accepted by the server if no other key is configured with WithApiKey
+const DefaultApiKey = "00000000-0000-0000-0000-000000000000"
+
+// Fault describes a scripted failure which is returned in place of the real response for an endpoint
+type Fault struct {
+	// Status is the HTTP status code which should be returned
+	Status int
+	// Body is the raw body which should be written in the response
+	Body string
+	// Times is the number of requests this fault should apply to before it is removed. If this is 0 then the fault
+	// will apply to every request until ClearFaults is called
+	Times int
+}
+
+// Server is a running DataPoint emulator. The embedded httptest.Server must be closed once the test is complete
+type Server struct {
+	*httptest.Server
+
+	mu               sync.Mutex
+	apiKey           string
+	dataDate         time.Time
+	forecastSites    []dp.Site
+	observationSites []dp.Site
+	regionalSites    []dp.RegionalForecastSite
+	extremes         dp.LatestExtremes
+	latency          time.Duration
+	quota            int
+	requests         int
+	singleObject     bool
+	faults           map[string]*Fault
+}
+
+// Opt is an option that can apply to a Server when it is created
+type Opt interface {
+	apply(server *Server)
+}
+
+type optFunc func(server *Server)
+
+func (o optFunc) apply(server *Server) {
+	o(server)
+}
+
+// WithApiKey sets the API key which the server will accept, any request without this key will be rejected
+func WithApiKey(key string) Opt {
+	return optFunc(func(server *Server) {
+		server.apiKey = key
+	})
+}
+
+// WithDataDate sets the time at which the served forecasts claim to have been run. All forecast time steps are
+// generated relative to this time
+func WithDataDate(date time.Time) Opt {
+	return optFunc(func(server *Server) {
+		server.dataDate = date.UTC()
+	})
+}
+
+// WithForecastSites replaces the default set of sites for which forecasts are served
+func WithForecastSites(sites []dp.Site) Opt {
+	return optFunc(func(server *Server) {
+		server.forecastSites = sites
+	})
+}
+
+// WithObservationSites replaces the default set of sites returned from the observation site list
+func WithObservationSites(sites []dp.Site) Opt {
+	return optFunc(func(server *Server) {
+		server.observationSites = sites
+	})
+}
+
+// WithRegionalSites replaces the default set of regions returned from the regional forecast site list
+func WithRegionalSites(sites []dp.RegionalForecastSite) Opt {
+	return optFunc(func(server *Server) {
+		server.regionalSites = sites
+	})
+}
+
+// WithExtremes replaces the default UK extremes which are served from the latest extremes endpoint
+func WithExtremes(extremes dp.LatestExtremes) Opt {
+	return optFunc(func(server *Server) {
+		server.extremes = extremes
+	})
+}
+
+// NewServer starts a new DataPoint emulator, applying all the options set. Without options the server will serve a
+// small fixed set of UK sites with forecasts generated deterministically from DefaultDataDate
+func NewServer(opt ...Opt) *Server {
+	s := &Server{
+		apiKey:           DefaultApiKey,
+		dataDate:         DefaultDataDate,
+		forecastSites:    DefaultForecastSites(),
+		observationSites: DefaultObservationSites(),
+		regionalSites:    DefaultRegionalSites(),
+		quota:            -1,
+		faults:           map[string]*Fault{},
+	}
+	s.extremes = DefaultExtremes(s.dataDate)
+
+	for _, o := range opt {
+		o.apply(s)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// BaseURI returns the URI which should be passed to datapoint.WithBaseURI to direct a client at this server
+func (s *Server) BaseURI() string {
+	return s.URL + "/public/data/"
+}
+
+// Client returns a new client which is configured to use this server and its API key. Any additional options are
+// applied after the defaults so can be used to override them
+func (s *Server) Client(opt ...dp.Opt) (*dp.DataPointClient, error) {
+	s.mu.Lock()
+	key := s.apiKey
+	s.mu.Unlock()
+
+	return dp.NewClient(append([]dp.Opt{
+		dp.WithApiKey(key),
+		dp.WithBaseURI(s.BaseURI()),
+		dp.WithHttpClient(s.Server.Client()),
+	}, opt...)...)
+}
+
+// InjectFault will cause requests to the endpoint with the given suffix (for example 'val/wxfcs/all/json/sitelist')
+// to fail with the fault provided. An empty suffix applies the fault to every endpoint
+func (s *Server) InjectFault(suffix string, fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[strings.Trim(suffix, "/")] = &fault
+}
+
+// ClearFaults removes all faults which have been injected
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = map[string]*Fault{}
+}
+
+// SetLatency delays every response by the given duration, or until the request is cancelled
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// SetQuota sets the number of further requests which will be accepted before the server reports that the quota for
+// the API key has been exhausted. A negative value removes the limit
+func (s *Server) SetQuota(remaining int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.quota = remaining
+}
+
+// SetSingleObjectQuirk toggles whether arrays containing exactly one element are returned as a bare object, which is
+// how the real service behaves
+func (s *Server) SetSingleObjectQuirk(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.singleObject = enabled
+}
+
+// Requests returns the number of requests the server has received, including rejected ones
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	latency := s.latency
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	suffix := strings.Trim(strings.TrimPrefix(r.URL.Path, "/public/data/"), "/")
+
+	s.mu.Lock()
+	fault := s.takeFault(suffix)
+	if fault == nil && r.URL.Query().Get("key") != s.apiKey {
+		fault = &Fault{Status: http.StatusForbidden, Body: "Invalid key"}
+	}
+	if fault == nil && s.quota == 0 {
+		fault = &Fault{Status: http.StatusForbidden, Body: "Quota exceeded"}
+	}
+	if fault == nil && s.quota > 0 {
+		s.quota--
+	}
+	s.mu.Unlock()
+
+	if fault != nil {
+		w.WriteHeader(fault.Status)
+		_, _ = w.Write([]byte(fault.Body))
+		return
+	}
+
+	body, ok := s.route(suffix, r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	if s.singleObject {
+		body = collapse(body)
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// takeFault must be called with the lock held
+func (s *Server) takeFault(suffix string) *Fault {
+	for _, key := range []string{suffix, ""} {
+		fault, ok := s.faults[key]
+		if !ok {
+			continue
+		}
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				delete(s.faults, key)
+			}
+		}
+		return fault
+	}
+	return nil
+}
+
+func (s *Server) route(suffix string, r *http.Request) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := r.URL.Query()
+	switch suffix {
+	case "val/wxfcs/all/json/sitelist":
+		return siteListBody(s.forecastSites), true
+	case "val/wxobs/all/json/sitelist":
+		return siteListBody(s.observationSites), true
+	case "val/wxfcs/all/json/capabilities":
+		return s.capabilitiesBody(dp.Resolution(query.Get("res"))), true
+	case "val/wxfcs/all/json/all":
+		return s.forecastBody(dp.Resolution(query.Get("res")), query.Get("time"), s.forecastSites, true), true
+	case "txt/wxobs/ukextremes/json/capabilities":
+		return s.extremesCapabilitiesBody(), true
+	case "txt/wxobs/ukextremes/json/latest":
+		return s.extremesBody(), true
+	case "txt/wxfcs/regionalforecast/json/sitelist":
+		return regionalSiteListBody(s.regionalSites), true
+	case "txt/wxfcs/regionalforecast/json/capabilities":
+		return s.regionalCapabilitiesBody(), true
+	}
+
+	if id, ok := strings.CutPrefix(suffix, "val/wxfcs/all/json/"); ok {
+		for _, site := range s.forecastSites {
+			if formatInt(site.Id) == id {
+				return s.forecastBody(dp.Resolution(query.Get("res")), query.Get("time"), []dp.Site{site}, false), true
+			}
+		}
+		return s.forecastBody(dp.Resolution(query.Get("res")), query.Get("time"), nil, false), true
+	}
+
+	return nil, false
+}
+
+// collapse replaces every array holding a single element with that element, mirroring the way the real service
+// serialises its XML backed responses
+func collapse(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = collapse(e)
+		}
+		return v
+	case []any:
+		if len(v) == 1 {
+			return collapse(v[0])
+		}
+		for i, e := range v {
+			v[i] = collapse(e)
+		}
+		return v
+	default:
+		return v
+	}
+}
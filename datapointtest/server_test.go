@@ -0,0 +1,325 @@
+package datapointtest_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	dp "github.com/vitineth/datapoint"
+	"github.com/vitineth/datapoint/datapointtest"
+)
+
+func newClient(t *testing.T, server *datapointtest.Server, opt ...dp.Opt) *dp.DataPointClient {
+	t.Helper()
+	client, err := server.Client(opt...)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func statusOf(t *testing.T, err error) int {
+	t.Helper()
+	var status *dp.StatusError
+	if !errors.As(err, &status) {
+		t.Fatalf("expected a StatusError but got %v", err)
+	}
+	return status.StatusCode
+}
+
+func TestSiteLists(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	client := newClient(t, server, dp.WithDecodingMode(dp.DecodingModeStrict))
+
+	forecast, err := client.ForecastSiteList()
+	if err != nil {
+		t.Fatalf("failed to fetch forecast site list: %v", err)
+	}
+	if len(forecast) != len(datapointtest.DefaultForecastSites()) {
+		t.Errorf("expected %v forecast sites but got %v", len(datapointtest.DefaultForecastSites()), len(forecast))
+	}
+	if !reflect.DeepEqual(forecast[0], datapointtest.DefaultForecastSites()[0]) {
+		t.Errorf("expected %+v but got %+v", datapointtest.DefaultForecastSites()[0], forecast[0])
+	}
+
+	observation, err := client.ObservationSiteList()
+	if err != nil {
+		t.Fatalf("failed to fetch observation site list: %v", err)
+	}
+	if len(observation) != len(datapointtest.DefaultObservationSites()) {
+		t.Errorf("expected %v observation sites but got %v", len(datapointtest.DefaultObservationSites()), len(observation))
+	}
+
+	regional, err := client.RegionalForecastSiteList()
+	if err != nil {
+		t.Fatalf("failed to fetch regional site list: %v", err)
+	}
+	if len(regional) != len(datapointtest.DefaultRegionalSites()) {
+		t.Errorf("expected %v regions but got %v", len(datapointtest.DefaultRegionalSites()), len(regional))
+	}
+}
+
+func TestForecasts(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	client := newClient(t, server, dp.WithDecodingMode(dp.DecodingModeStrict))
+	site := datapointtest.DefaultForecastSites()[0]
+
+	steps, err := client.ForecastTimeStepCapabilities(dp.ResolutionThreeHourly)
+	if err != nil {
+		t.Fatalf("failed to fetch capabilities: %v", err)
+	}
+	if len(steps.TimeSteps) == 0 {
+		t.Fatal("expected time steps to be available")
+	}
+
+	threeHourly, err := client.FiveDayForecast(dp.ResolutionThreeHourly, site.Id, nil)
+	if err != nil {
+		t.Fatalf("failed to fetch three hourly forecast: %v", err)
+	}
+	if threeHourly.Location.Id != site.Id || len(threeHourly.Location.Period) == 0 {
+		t.Errorf("unexpected three hourly forecast %+v", threeHourly.Location)
+	}
+	if _, ok := threeHourly.Location.Period[0].Forecasts[0].Temperature(); !ok {
+		t.Error("expected the three hourly forecast to include a temperature")
+	}
+
+	daily, err := client.FiveDayForecast(dp.ResolutionDaily, site.Id, nil)
+	if err != nil {
+		t.Fatalf("failed to fetch daily forecast: %v", err)
+	}
+	for _, period := range daily.Location.Period {
+		for _, f := range period.Forecasts {
+			if f.Segment == dp.SegmentNone {
+				t.Errorf("expected daily forecast at %v to have a segment", f.Time)
+			}
+		}
+	}
+
+	all, err := client.FiveDayForecastForAllLocations(dp.ResolutionThreeHourly, &steps.TimeSteps[0])
+	if err != nil {
+		t.Fatalf("failed to fetch all locations: %v", err)
+	}
+	if len(all) != len(datapointtest.DefaultForecastSites()) {
+		t.Errorf("expected %v locations but got %v", len(datapointtest.DefaultForecastSites()), len(all))
+	}
+
+	unknown, err := client.FiveDayForecast(dp.ResolutionThreeHourly, 1, nil)
+	if err != nil || unknown != nil {
+		t.Errorf("expected no forecast for an unknown site but got %v, %v", unknown, err)
+	}
+}
+
+func TestExtremes(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	client := newClient(t, server, dp.WithDecodingMode(dp.DecodingModeStrict))
+
+	extremes, err := client.UkExtremesLatest()
+	if err != nil {
+		t.Fatalf("failed to fetch extremes: %v", err)
+	}
+	expected := datapointtest.DefaultExtremes(datapointtest.DefaultDataDate)
+	if !extremes.ExtremeDate.Equal(expected.ExtremeDate) || len(extremes.Regions) != len(expected.Regions) {
+		t.Errorf("expected %+v but got %+v", expected, extremes)
+	}
+}
+
+func TestInvalidKey(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	client := newClient(t, server, dp.WithApiKey("not-the-key"))
+
+	_, err := client.ForecastSiteList()
+	if status := statusOf(t, err); status != http.StatusForbidden {
+		t.Errorf("expected status %v but got %v", http.StatusForbidden, status)
+	}
+}
+
+func TestQuota(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	client := newClient(t, server)
+
+	server.SetQuota(1)
+	if _, err := client.ForecastSiteList(); err != nil {
+		t.Fatalf("expected the first request to succeed: %v", err)
+	}
+	_, err := client.ForecastSiteList()
+	if status := statusOf(t, err); status != http.StatusForbidden {
+		t.Errorf("expected status %v but got %v", http.StatusForbidden, status)
+	}
+
+	server.SetQuota(-1)
+	if _, err := client.ForecastSiteList(); err != nil {
+		t.Errorf("expected requests to succeed once the quota was removed: %v", err)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	client := newClient(t, server)
+
+	server.InjectFault("val/wxfcs/all/json/sitelist", datapointtest.Fault{
+		Status: http.StatusServiceUnavailable,
+		Body:   "Service unavailable",
+		Times:  1,
+	})
+	_, err := client.ForecastSiteList()
+	if status := statusOf(t, err); status != http.StatusServiceUnavailable {
+		t.Errorf("expected status %v but got %v", http.StatusServiceUnavailable, status)
+	}
+	if _, err := client.ObservationSiteList(); err != nil {
+		t.Errorf("expected other endpoints to be unaffected: %v", err)
+	}
+	if _, err := client.ForecastSiteList(); err != nil {
+		t.Errorf("expected the fault to apply only once: %v", err)
+	}
+
+	server.InjectFault("", datapointtest.Fault{Status: http.StatusInternalServerError, Body: "Internal error"})
+	_, err = client.UkExtremesLatest()
+	if status := statusOf(t, err); status != http.StatusInternalServerError {
+		t.Errorf("expected status %v but got %v", http.StatusInternalServerError, status)
+	}
+	server.ClearFaults()
+	if _, err := client.UkExtremesLatest(); err != nil {
+		t.Errorf("expected requests to succeed once faults were cleared: %v", err)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	httpClient := server.Server.Client()
+	httpClient.Timeout = 50 * time.Millisecond
+	client := newClient(t, server, dp.WithHttpClient(httpClient))
+
+	server.SetLatency(time.Second)
+	if _, err := client.ForecastSiteList(); err == nil {
+		t.Error("expected the request to time out")
+	}
+}
+
+func TestSingleObjectQuirk(t *testing.T) {
+	server := datapointtest.NewServer(datapointtest.WithForecastSites(datapointtest.DefaultForecastSites()[:1]))
+	defer server.Close()
+	server.SetSingleObjectQuirk(true)
+
+	for _, mode := range []dp.DecodingMode{dp.DecodingModeDefault, dp.DecodingModeLenient, dp.DecodingModeStrict} {
+		client := newClient(t, server, dp.WithDecodingMode(mode))
+
+		sites, err := client.ForecastSiteList()
+		if err != nil {
+			t.Fatalf("mode %v: failed to fetch site list: %v", mode, err)
+		}
+		if len(sites) != 1 || len(sites[0].Warnings) != 0 {
+			t.Errorf("mode %v: expected a single site without warnings but got %+v", mode, sites)
+		}
+
+		at := datapointtest.DefaultDataDate
+		rep, err := client.FiveDayForecast(dp.ResolutionThreeHourly, sites[0].Id, &at)
+		if err != nil {
+			t.Fatalf("mode %v: failed to fetch forecast: %v", mode, err)
+		}
+		if len(rep.Location.Period) != 1 || len(rep.Location.Period[0].Forecasts) != 1 || len(rep.Warnings) != 0 {
+			t.Errorf("mode %v: expected a single forecast without warnings but got %+v", mode, rep)
+		}
+
+		all, err := client.FiveDayForecastForAllLocations(dp.ResolutionThreeHourly, &at)
+		if err != nil {
+			t.Fatalf("mode %v: failed to fetch all locations: %v", mode, err)
+		}
+		if len(all) != 1 {
+			t.Errorf("mode %v: expected a single location but got %v", mode, len(all))
+		}
+	}
+}
+
+func TestForecastForAllLocationsFunc(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	server.SetSingleObjectQuirk(true)
+	client := newClient(t, server, dp.WithDecodingMode(dp.DecodingModeStrict))
+
+	for _, resolution := range []dp.Resolution{dp.ResolutionThreeHourly, dp.ResolutionDaily} {
+		expected, err := client.FiveDayForecastForAllLocations(resolution, nil)
+		if err != nil {
+			t.Fatalf("failed to fetch all locations: %v", err)
+		}
+
+		var streamed []dp.SiteRep
+		err = client.FiveDayForecastForAllLocationsFunc(resolution, nil, func(rep dp.SiteRep) error {
+			streamed = append(streamed, rep)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("failed to stream all locations: %v", err)
+		}
+		if !reflect.DeepEqual(expected, streamed) {
+			t.Errorf("streamed %v forecasts do not match the decoded forecasts", resolution)
+		}
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err := client.FiveDayForecastForAllLocationsFunc(dp.ResolutionThreeHourly, nil, func(rep dp.SiteRep) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("expected decoding to stop after the first location but got %v after %v calls", err, calls)
+	}
+}
+
+func TestMalformedElevation(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	server.InjectFault("val/wxfcs/all/json/sitelist", datapointtest.Fault{
+		Status: http.StatusOK,
+		Body:   `{"Locations":{"Location":[{"elevation":"high","id":"1","latitude":"50.0","longitude":"-3.0","name":"A"}]}}`,
+	})
+
+	for _, mode := range []dp.DecodingMode{dp.DecodingModeDefault, dp.DecodingModeLenient} {
+		sites, err := newClient(t, server, dp.WithDecodingMode(mode)).ForecastSiteList()
+		if err != nil {
+			t.Fatalf("mode %v: failed to fetch site list: %v", mode, err)
+		}
+		if len(sites) != 1 || len(sites[0].Warnings) == 0 {
+			t.Errorf("mode %v: expected a warning for the elevation but got %+v", mode, sites)
+		}
+	}
+}
+
+func TestMalformedParameter(t *testing.T) {
+	const body = `{"SiteRep":{"Wx":{"Param":[{"$":"Temperature","name":"T","units":"C"}]},"DV":{"dataDate":"2024-03-14T15:00:00Z","type":"Forecast","Location":{"i":"310069","lat":"50.7179","lon":"-3.5327","name":"Exeter","country":"UNITED KINGDOM","continent":"EUROPE","elevation":"27.0","Period":{"type":"Day","value":"2024-03-14Z","Rep":{"$":"1080","T":%q,"X":"extra"}}}}}}`
+	server := datapointtest.NewServer()
+	defer server.Close()
+	site := datapointtest.DefaultForecastSites()[0]
+
+	for _, mode := range []dp.DecodingMode{dp.DecodingModeDefault, dp.DecodingModeLenient, dp.DecodingModeStrict} {
+		client := newClient(t, server, dp.WithDecodingMode(mode))
+
+		server.InjectFault("val/wxfcs/all/json/310069", datapointtest.Fault{Status: http.StatusOK, Body: fmt.Sprintf(body, "warm"), Times: 1})
+		if _, err := client.FiveDayForecast(dp.ResolutionThreeHourly, site.Id, nil); err == nil {
+			t.Errorf("mode %v: expected a malformed temperature to fail", mode)
+		}
+
+		if mode == dp.DecodingModeStrict {
+			continue
+		}
+		server.InjectFault("val/wxfcs/all/json/310069", datapointtest.Fault{Status: http.StatusOK, Body: fmt.Sprintf(body, "13"), Times: 1})
+		rep, err := client.FiveDayForecast(dp.ResolutionThreeHourly, site.Id, nil)
+		if err != nil {
+			t.Fatalf("mode %v: failed to fetch forecast: %v", mode, err)
+		}
+		f := rep.Location.Period[0].Forecasts[0]
+		if f.IntParams["T"].Value != 13 || f.StringParams["X"].Value != "extra" {
+			t.Errorf("mode %v: expected the temperature as an int and the unknown parameter as a string but got %+v", mode, f)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package datapoint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// DecodingMode controls how the client reacts when a response does not match the schema it expects
+type DecodingMode int
+
+const (
+	// DecodingModeDefault decodes responses without comparing them to the expected schema, which avoids parsing every
+	// response twice. Fields which are not recognised are ignored, but values which cannot be interpreted are still
+	// recorded as a Warning on the returned value. This is the default
+	DecodingModeDefault DecodingMode = iota
+	// DecodingModeLenient accepts responses which do not match the expected schema, recording each difference as a
+	// Warning on the returned value
+	DecodingModeLenient
+	// DecodingModeStrict fails with an error if a response contains unknown fields, is missing expected fields or
+	// contains values which cannot be interpreted
+	DecodingModeStrict
+)
+
+type decodingMode struct {
+	mode DecodingMode
+}
+
+func (m decodingMode) apply(client *DataPointClient) {
+	client.decodingMode = m.mode
+}
+
+// WithDecodingMode sets how the client should handle responses which do not match the expected schema. This can be
+// used to notice when the Met Office changes one of the feeds
+func WithDecodingMode(mode DecodingMode) Opt {
+	return decodingMode{mode: mode}
+}
+
+// Warning describes a single difference between a response and the schema expected by the client
+type Warning struct {
+	// Path is the location in the response at which the difference was found, e.g. 'Locations.Location[3].elevation'
+	Path string
+	// Message describes the difference
+	Message string
+}
+
+func (w Warning) Error() string {
+	return w.Path + ": " + w.Message
+}
+
+// decoder collects warnings while a single response is being converted
+type decoder struct {
+	mode     DecodingMode
+	warnings []Warning
+}
+
+func (d *DataPointClient) newDecoder() *decoder {
+	return &decoder{mode: d.decodingMode}
+}
+
+// checking returns if the decoder compares responses against the schema and records warnings
+func (dec *decoder) checking() bool {
+	return dec.mode != DecodingModeDefault
+}
+
+// warn records a difference in every mode. Differences which are only found by comparing against the schema must be
+// guarded by checking
+func (dec *decoder) warn(path string, format string, args ...any) {
+	dec.warnings = append(dec.warnings, Warning{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// checkSchema compares the raw body against the shape of v, recording a warning for every field which is present in
+// the body but not in v and every field in v which is missing from the body. Fields tagged with dp:"optional" may be
+// omitted without a warning. Nothing is checked in DecodingModeDefault
+func (dec *decoder) checkSchema(body []byte, v any) {
+	if !dec.checking() {
+		return
+	}
+
+	var raw any
+	err := json.Unmarshal(body, &raw)
+	if err != nil {
+		dec.warn("", "failed to deserialise body for schema check: %v", err)
+		return
+	}
+	dec.checkValue(raw, reflect.TypeOf(v), "")
+}
+
+func (dec *decoder) checkValue(raw any, t reflect.Type, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			return
+		}
+
+		known := map[string]bool{}
+		for i := range t.NumField() {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			known[name] = true
+
+			value, ok := obj[name]
+			if !ok {
+				if field.Tag.Get("dp") != "optional" {
+					dec.warn(joinPath(path, name), "missing field")
+				}
+				continue
+			}
+			dec.checkValue(value, field.Type, joinPath(path, name))
+		}
+
+		var unknown []string
+		for k := range obj {
+			if !known[k] {
+				unknown = append(unknown, k)
+			}
+		}
+		slices.Sort(unknown)
+		for _, k := range unknown {
+			dec.warn(joinPath(path, k), "unknown field")
+		}
+	case reflect.Slice:
+		arr, ok := raw.([]any)
+		if !ok {
+			if raw == nil {
+				return
+			}
+			// the service returns a bare element in place of an array holding a single element
+			arr = []any{raw}
+		}
+		for i, e := range arr {
+			dec.checkValue(e, t.Elem(), fmt.Sprintf("%v[%d]", path, i))
+		}
+	}
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// split divides the collected warnings into those under the element at prefix[i] for each of the n elements, and
+// those which apply to the response as a whole. The shared warnings are included once, in the first element
+func (dec *decoder) split(prefix string, n int) [][]Warning {
+	var shared []Warning
+	elements := make([][]Warning, n)
+	for _, w := range dec.warnings {
+		index := -1
+		if rest, ok := strings.CutPrefix(w.Path, prefix+"["); ok {
+			end := strings.Index(rest, "]")
+			if end > 0 {
+				_, err := fmt.Sscanf(rest[:end], "%d", &index)
+				if err != nil || index >= n {
+					index = -1
+				}
+			}
+		}
+
+		if index < 0 {
+			shared = append(shared, w)
+		} else {
+			elements[index] = append(elements[index], w)
+		}
+	}
+
+	if len(shared) > 0 && n > 0 {
+		elements[0] = append(shared, elements[0]...)
+	}
+	return elements
+}
+
+// result returns the collected warnings, or an error if the decoder is strict and any warnings were raised
+func (dec *decoder) result(target string) ([]Warning, error) {
+	if dec.mode == DecodingModeStrict && len(dec.warnings) > 0 {
+		return nil, dec.strictError(target)
+	}
+	return dec.warnings, nil
+}
+
+func (dec *decoder) strictError(target string) error {
+	errs := make([]error, len(dec.warnings))
+	for i, w := range dec.warnings {
+		errs[i] = w
+	}
+	return fmt.Errorf("response from %v did not match the expected schema: %w", target, errors.Join(errs...))
+}
+
+// oneOrMany is a slice which also accepts a bare element in place of an array, which is how the service returns
+// arrays holding a single element
+type oneOrMany[T any] []T
+
+func (o *oneOrMany[T]) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] == '[' || bytes.Equal(data, []byte("null")) {
+		var values []T
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		*o = values
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*o = oneOrMany[T]{value}
+	return nil
+}
@@ -0,0 +1,255 @@
+package datapoint
+
+import (
+	"math"
+	"strconv"
+)
+
+// DewPoint returns the dew point in °C for an air temperature in °C and a relative humidity in percent, using the
+// Magnus formula
+func DewPoint(temperature float64, humidity float64) float64 {
+	const a, b = 17.62, 243.12
+	gamma := math.Log(humidity/100) + a*temperature/(b+temperature)
+	return b * gamma / (a - gamma)
+}
+
+// RelativeHumidity returns the relative humidity in percent for an air temperature and dew point in °C, the inverse
+// of DewPoint
+func RelativeHumidity(temperature float64, dewPoint float64) float64 {
+	const a, b = 17.62, 243.12
+	return 100 * math.Exp(a*dewPoint/(b+dewPoint)-a*temperature/(b+temperature))
+}
+
+// HeatIndex returns the apparent temperature in °C caused by humidity, using the US National Weather Service
+// algorithm. The index is only meaningful in warm conditions and tends towards the air temperature when it is cool
+func HeatIndex(temperature float64, humidity float64) float64 {
+	t := temperature*9/5 + 32
+	simple := 0.5 * (t + 61 + (t-68)*1.2 + humidity*0.094)
+	if (simple+t)/2 < 80 {
+		return (simple - 32) * 5 / 9
+	}
+
+	hi := -42.379 + 2.04901523*t + 10.14333127*humidity - 0.22475541*t*humidity - 0.00683783*t*t -
+		0.05481717*humidity*humidity + 0.00122874*t*t*humidity + 0.00085282*t*humidity*humidity -
+		0.00000199*t*t*humidity*humidity
+	if humidity < 13 && t >= 80 && t <= 112 {
+		hi -= (13 - humidity) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+	} else if humidity > 85 && t >= 80 && t <= 87 {
+		hi += (humidity - 85) / 10 * (87 - t) / 5
+	}
+	return (hi - 32) * 5 / 9
+}
+
+// WindChill returns the apparent temperature in °C caused by wind, using the JAG/TI formula adopted by the Met Office
+// and Environment Canada. The formula is only defined at or below 10°C with wind above 4.8 km/h, outside of which the
+// air temperature is returned
+func WindChill(temperature float64, windSpeedKmh float64) float64 {
+	if temperature > 10 || windSpeedKmh <= 4.8 {
+		return temperature
+	}
+	v := math.Pow(windSpeedKmh, 0.16)
+	return 13.12 + 0.6215*temperature - 11.37*v + 0.3965*temperature*v
+}
+
+// heatIndexThreshold is the temperature in °C, 80°F, from which the heat index is used as the apparent temperature
+const heatIndexThreshold = (80 - 32) * 5.0 / 9.0
+
+// ApparentTemperature returns the feels like temperature in °C for an air temperature in °C, relative humidity in
+// percent and wind speed in km/h. This is the wind chill when it is cold and windy, the heat index when it is hot and
+// the air temperature otherwise. It can be used for observations, which do not report a feels like temperature
+func ApparentTemperature(temperature float64, humidity float64, windSpeedKmh float64) float64 {
+	switch {
+	case temperature <= 10 && windSpeedKmh > 4.8:
+		return WindChill(temperature, windSpeedKmh)
+	case temperature >= heatIndexThreshold:
+		return HeatIndex(temperature, humidity)
+	default:
+		return temperature
+	}
+}
+
+// Humidex returns the Canadian humidex in °C for an air temperature and dew point in °C
+func Humidex(temperature float64, dewPoint float64) float64 {
+	e := 6.11 * math.Exp(5417.7530*(1/273.16-1/(273.15+dewPoint)))
+	return temperature + 0.5555*(e-10)
+}
+
+// WetBulbTemperature returns the wet bulb temperature in °C for an air temperature in °C and relative humidity in
+// percent, using the approximation from Stull (2011) which is accurate to within 1°C at sea level pressure
+func WetBulbTemperature(temperature float64, humidity float64) float64 {
+	return temperature*math.Atan(0.151977*math.Sqrt(humidity+8.313659)) +
+		math.Atan(temperature+humidity) - math.Atan(humidity-1.676331) +
+		0.00391838*math.Pow(humidity, 1.5)*math.Atan(0.023101*humidity) - 4.686035
+}
+
+// FrostRisk returns if frost or ice is likely to form. This is the case when the air temperature is at or below
+// freezing, when it is within 3°C of freezing and the dew point is below freezing so ground frost can form, or when
+// it is within 1°C of freezing and precipitation is expected so wet surfaces can freeze
+func FrostRisk(temperature float64, dewPoint float64, precipitation bool) bool {
+	switch {
+	case temperature <= 0:
+		return true
+	case temperature <= 3 && dewPoint <= 0:
+		return true
+	case temperature <= 1 && precipitation:
+		return true
+	default:
+		return false
+	}
+}
+
+// number returns the value of the first parameter present on the forecast converted into the unit. Integer, decimal
+// and string parameters are all considered so that observations, which report decimal values, are supported
+func (f Forecast) number(to Unit, params ...KnownParameter) (float64, bool) {
+	for _, p := range params {
+		var value float64
+		var descriptor ParameterDescriptor
+		if v, ok := f.IntParams[string(p)]; ok {
+			value, descriptor = float64(v.Value), v.ParameterDescriptor
+		} else if v, ok := f.FloatParams[string(p)]; ok {
+			value, descriptor = v.Value, v.ParameterDescriptor
+		} else if v, ok := f.StringParams[string(p)]; ok {
+			parsed, err := strconv.ParseFloat(v.Value, 64)
+			if err != nil {
+				continue
+			}
+			value, descriptor = parsed, v.ParameterDescriptor
+		} else {
+			continue
+		}
+
+		from, err := descriptor.Unit()
+		if err != nil || from == UnitNone {
+			// parameters without a descriptor, or whose units are missing, are assumed to use the standard units for the
+			// parameter
+			var ok bool
+			if from, ok = standardUnit(string(p)); !ok {
+				continue
+			}
+		}
+		converted, err := Convert(value, from, to)
+		if err != nil {
+			continue
+		}
+		return converted, true
+	}
+	return 0, false
+}
+
+func (f Forecast) temperatureCelsius() (float64, bool) {
+	return f.number(UnitCelsius, KnownParameterTemperature, KnownParameterDayMaximumTemperature, KnownParameterNightMinimumTemperature)
+}
+
+func (f Forecast) humidityPercent() (float64, bool) {
+	return f.number(UnitPercent, KnownParameterScreenRelativeHumidity, KnownParameterScreenRelativeHumidityNoon, KnownParameterScreenRelativeHumidityMidnight)
+}
+
+// DewPoint returns the dew point in °C. Observations report this directly, otherwise it is calculated from the
+// temperature and humidity
+func (f Forecast) DewPoint() (float64, bool) {
+	if dp, ok := f.number(UnitCelsius, KnownParameterDewPoint); ok {
+		return dp, true
+	}
+
+	t, ok := f.temperatureCelsius()
+	if !ok {
+		return 0, false
+	}
+	h, ok := f.humidityPercent()
+	if !ok || h <= 0 {
+		return 0, false
+	}
+	return DewPoint(t, h), true
+}
+
+// HeatIndex returns the heat index in °C calculated from the temperature and humidity
+func (f Forecast) HeatIndex() (float64, bool) {
+	t, ok := f.temperatureCelsius()
+	if !ok {
+		return 0, false
+	}
+	h, ok := f.humidityPercent()
+	if !ok {
+		return 0, false
+	}
+	return HeatIndex(t, h), true
+}
+
+// WindChill returns the wind chill in °C calculated from the temperature and wind speed
+func (f Forecast) WindChill() (float64, bool) {
+	t, ok := f.temperatureCelsius()
+	if !ok {
+		return 0, false
+	}
+	s, ok := f.number(UnitKilometresPerHour, KnownParameterWindSpeed)
+	if !ok {
+		return 0, false
+	}
+	return WindChill(t, s), true
+}
+
+// ApparentTemperature returns the feels like temperature in °C calculated from the temperature, humidity and wind
+// speed, as described by the ApparentTemperature function. Without a humidity the heat index is not applied, and
+// without a wind speed the wind chill is not applied
+func (f Forecast) ApparentTemperature() (float64, bool) {
+	t, ok := f.temperatureCelsius()
+	if !ok {
+		return 0, false
+	}
+	s, ok := f.number(UnitKilometresPerHour, KnownParameterWindSpeed)
+	if !ok {
+		s = 0
+	}
+	h, ok := f.humidityPercent()
+	if !ok && t >= heatIndexThreshold {
+		return t, true
+	}
+	return ApparentTemperature(t, h, s), true
+}
+
+// Humidex returns the humidex in °C calculated from the temperature and dew point
+func (f Forecast) Humidex() (float64, bool) {
+	t, ok := f.temperatureCelsius()
+	if !ok {
+		return 0, false
+	}
+	dp, ok := f.DewPoint()
+	if !ok {
+		return 0, false
+	}
+	return Humidex(t, dp), true
+}
+
+// WetBulbTemperature returns the wet bulb temperature in °C calculated from the temperature and humidity. If only the
+// dew point is available the humidity is derived from it
+func (f Forecast) WetBulbTemperature() (float64, bool) {
+	t, ok := f.temperatureCelsius()
+	if !ok {
+		return 0, false
+	}
+	h, ok := f.humidityPercent()
+	if !ok {
+		dp, ok := f.number(UnitCelsius, KnownParameterDewPoint)
+		if !ok {
+			return 0, false
+		}
+		h = RelativeHumidity(t, dp)
+	}
+	return WetBulbTemperature(t, h), true
+}
+
+// FrostRisk returns if frost or ice is likely, as described by the FrostRisk function. Precipitation is taken from
+// the weather type. The second value is false if the forecast has no temperature
+func (f Forecast) FrostRisk() (bool, bool) {
+	t, ok := f.temperatureCelsius()
+	if !ok {
+		return false, false
+	}
+	dp, ok := f.DewPoint()
+	if !ok {
+		// without a dew point only the temperature and precipitation checks can apply
+		dp = t
+	}
+	w, _ := f.WeatherType()
+	return FrostRisk(t, dp, w.IsPrecipitation()), true
+}
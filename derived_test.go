@@ -0,0 +1,125 @@
+package datapoint_test
+
+import (
+	"math"
+	"testing"
+
+	dp "github.com/vitineth/datapoint"
+)
+
+func fahrenheit(celsius float64) float64 {
+	return celsius*9/5 + 32
+}
+
+func celsius(fahrenheit float64) float64 {
+	return (fahrenheit - 32) * 5 / 9
+}
+
+func TestHeatIndex(t *testing.T) {
+	// reference values from the US National Weather Service heat index chart
+	tests := []struct {
+		temperature, humidity, expected float64
+	}{
+		{80, 40, 80},
+		{84, 90, 98},
+		{90, 70, 106},
+		{96, 65, 121},
+		{100, 10, 94},
+	}
+	for _, test := range tests {
+		actual := fahrenheit(dp.HeatIndex(celsius(test.temperature), test.humidity))
+		if math.Abs(actual-test.expected) > 1 {
+			t.Errorf("expected a heat index of %v°F at %v°F and %v%% but got %.1f°F", test.expected, test.temperature, test.humidity, actual)
+		}
+	}
+}
+
+func TestWindChill(t *testing.T) {
+	// reference values from the Environment Canada wind chill table
+	tests := []struct {
+		temperature, speed, expected float64
+	}{
+		{5, 40, -1},
+		{0, 10, -3},
+		{-10, 20, -18},
+		{-20, 30, -33},
+		{15, 30, 15},
+		{-5, 3, -5},
+	}
+	for _, test := range tests {
+		actual := dp.WindChill(test.temperature, test.speed)
+		if math.Abs(actual-test.expected) > 0.5 {
+			t.Errorf("expected a wind chill of %v°C at %v°C and %v km/h but got %.1f°C", test.expected, test.temperature, test.speed, actual)
+		}
+	}
+}
+
+func TestDewPoint(t *testing.T) {
+	// reference values from the NOAA dew point calculator
+	tests := []struct {
+		temperature, humidity, expected float64
+	}{
+		{20, 50, 9.3},
+		{30, 70, 23.9},
+		{10, 90, 8.4},
+	}
+	for _, test := range tests {
+		actual := dp.DewPoint(test.temperature, test.humidity)
+		if math.Abs(actual-test.expected) > 0.1 {
+			t.Errorf("expected a dew point of %v°C at %v°C and %v%% but got %.2f°C", test.expected, test.temperature, test.humidity, actual)
+		}
+		if h := dp.RelativeHumidity(test.temperature, actual); math.Abs(h-test.humidity) > 1e-9 {
+			t.Errorf("expected the humidity at %v°C to round trip to %v%% but got %v%%", test.temperature, test.humidity, h)
+		}
+	}
+}
+
+func TestApparentTemperature(t *testing.T) {
+	tests := []struct {
+		name                         string
+		temperature, humidity, speed float64
+		expected                     float64
+	}{
+		{"cold and windy uses the wind chill", -10, 80, 20, -18},
+		{"hot and humid uses the heat index", celsius(90), 70, 20, celsius(106)},
+		{"mild uses the air temperature", 18, 90, 40, 18},
+		{"cold and calm uses the air temperature", 2, 90, 3, 2},
+	}
+	for _, test := range tests {
+		actual := dp.ApparentTemperature(test.temperature, test.humidity, test.speed)
+		if math.Abs(actual-test.expected) > 0.6 {
+			t.Errorf("%v: expected %.1f°C but got %.1f°C", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestForecastDerivedUnits(t *testing.T) {
+	forecast := func(units bool) dp.Forecast {
+		descriptor := func(name string, u string) dp.ParameterDescriptor {
+			if !units {
+				u = ""
+			}
+			return dp.ParameterDescriptor{Name: name, Units: u}
+		}
+		return dp.Forecast{IntParams: map[string]dp.IntParameterValue{
+			"T": {ParameterDescriptor: descriptor("T", "C"), Value: -10},
+			"S": {ParameterDescriptor: descriptor("S", "mph"), Value: 12},
+			"H": {ParameterDescriptor: descriptor("H", "%"), Value: 80},
+		}}
+	}
+
+	// parameters without units use the standard units of the parameter, so the wind speed is still read as mph
+	expected := dp.WindChill(-10, 12*1.609344)
+	for _, units := range []bool{true, false} {
+		f := forecast(units)
+		if chill, ok := f.WindChill(); !ok || math.Abs(chill-expected) > 1e-9 {
+			t.Errorf("units %v: expected a wind chill of %.2f°C but got %.2f, %v", units, expected, chill, ok)
+		}
+		if apparent, ok := f.ApparentTemperature(); !ok || math.Abs(apparent-expected) > 1e-9 {
+			t.Errorf("units %v: expected a feels like temperature of %.2f°C but got %.2f, %v", units, expected, apparent, ok)
+		}
+		if dew, ok := f.DewPoint(); !ok || math.Abs(dew-dp.DewPoint(-10, 80)) > 1e-9 {
+			t.Errorf("units %v: expected a dew point of %.2f°C but got %.2f, %v", units, dp.DewPoint(-10, 80), dew, ok)
+		}
+	}
+}
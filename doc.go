@@ -39,31 +39,15 @@ This will give you a quick setup with the DataPoint library and show you how to
 	for _, period := range forecast.Location.Period {
 		fmt.Printf("  %v (%v)\n", period.Time, period.Type)
 		for _, fore := range period.Forecasts {
-			temp := 0
-			unit := ""
-			t, ok := fore.IntParams[string(dp.KnownParameterTemperature)]
-			if ok {
-				temp = t.Value
-				unit = t.Units
-			} else {
-				t, ok = fore.IntParams[string(dp.KnownParameterDayMaximumTemperature)]
-				if ok {
-					temp = t.Value
-					unit = t.Units
-				} else {
-					t, ok = fore.IntParams[string(dp.KnownParameterNightMinimumTemperature)]
-					if ok {
-						temp = t.Value
-						unit = t.Units
-					} else {
-						panic("Could not find temperature in forecast!")
-					}
-				}
+			t, ok := fore.Temperature()
+			if !ok {
+				panic("Could not find temperature in forecast!")
 			}
-			fmt.Printf("    %v: %v%v\n", fore.Time, temp, unit)
+			fmt.Printf("    %v: %v%v\n", fore.Time, t.Value, t.Units)
 		}
 	}
 
-Note: this is quite a bad way to do this, but it gives you an idea
+Temperature picks the right parameter for the resolution of the forecast, using T for three hourly forecasts and Dm or
+Nm for the day and night of daily forecasts
 */
 package datapoint
@@ -0,0 +1,124 @@
+package datapoint
+
+// ExtremeType is the code of the kind of extreme reported by the UK extremes feed, e.g. 'HMAXT'
+type ExtremeType string
+
+const (
+	ExtremeTypeHighestMaximumTemperature ExtremeType = "HMAXT"
+	ExtremeTypeLowestMaximumTemperature  ExtremeType = "LMAXT"
+	ExtremeTypeHighestMinimumTemperature ExtremeType = "HMINT"
+	ExtremeTypeLowestMinimumTemperature  ExtremeType = "LMINT"
+	ExtremeTypeHighestRainfall           ExtremeType = "HRAIN"
+	ExtremeTypeHighestSunshine           ExtremeType = "HSUN"
+)
+
+// ExtremeMeasure is the kind of value an extreme records
+type ExtremeMeasure int
+
+const (
+	ExtremeMeasureUnknown ExtremeMeasure = iota
+	ExtremeMeasureTemperature
+	ExtremeMeasureRainfall
+	ExtremeMeasureSunshine
+)
+
+type extremeTypeInfo struct {
+	measure      ExtremeMeasure
+	higherIsMore bool
+}
+
+var extremeTypes = map[ExtremeType]extremeTypeInfo{
+	ExtremeTypeHighestMaximumTemperature: {ExtremeMeasureTemperature, true},
+	ExtremeTypeLowestMaximumTemperature:  {ExtremeMeasureTemperature, false},
+	ExtremeTypeHighestMinimumTemperature: {ExtremeMeasureTemperature, true},
+	ExtremeTypeLowestMinimumTemperature:  {ExtremeMeasureTemperature, false},
+	ExtremeTypeHighestRainfall:           {ExtremeMeasureRainfall, true},
+	ExtremeTypeHighestSunshine:           {ExtremeMeasureSunshine, true},
+}
+
+// ExtremeTypes returns every extreme type known to the client
+func ExtremeTypes() []ExtremeType {
+	return []ExtremeType{
+		ExtremeTypeHighestMaximumTemperature,
+		ExtremeTypeLowestMaximumTemperature,
+		ExtremeTypeHighestMinimumTemperature,
+		ExtremeTypeLowestMinimumTemperature,
+		ExtremeTypeHighestRainfall,
+		ExtremeTypeHighestSunshine,
+	}
+}
+
+// IsKnown returns if the extreme type is one of the documented codes
+func (t ExtremeType) IsKnown() bool {
+	_, ok := extremeTypes[t]
+	return ok
+}
+
+// String returns the English label of the extreme type, e.g. 'Highest maximum temperature'. Unknown types return
+// their code
+func (t ExtremeType) String() string {
+	return t.Localise(LanguageEnglish)
+}
+
+// Localise returns the label of the extreme type in the language
+func (t ExtremeType) Localise(language Language) string {
+	return LocaliseExtremeType(string(t), language)
+}
+
+// Measure returns the kind of value the extreme records
+func (t ExtremeType) Measure() ExtremeMeasure {
+	return extremeTypes[t].measure
+}
+
+// HigherIsMoreExtreme returns if a larger value is more extreme for this type, which is false for the lowest
+// temperature types. Unknown types return true
+func (t ExtremeType) HigherIsMoreExtreme() bool {
+	info, ok := extremeTypes[t]
+	return !ok || info.higherIsMore
+}
+
+// IsMoreExtreme returns if the value a is more extreme than b for this type
+func (t ExtremeType) IsMoreExtreme(a float64, b float64) bool {
+	if t.HigherIsMoreExtreme() {
+		return a > b
+	}
+	return a < b
+}
+
+// Unit returns the parsed unit of measurement of the extreme
+func (e Extreme) Unit() (Unit, error) {
+	return ParseUnit(e.UnitOfMeasurement)
+}
+
+// NationalExtreme returns the most extreme reading of the type across every region. If several readings are equally
+// extreme the first is returned. The second value is false if no region reported the type
+func (l LatestExtremes) NationalExtreme(t ExtremeType) (Extreme, bool) {
+	var result Extreme
+	found := false
+	for _, region := range l.Regions {
+		for _, e := range region.Extremes {
+			if e.Type != t {
+				continue
+			}
+			if !found || t.IsMoreExtreme(e.Value, result.Value) {
+				result = e
+				found = true
+			}
+		}
+	}
+	return result, found
+}
+
+// NationalExtremes returns the most extreme reading of every type reported across all regions
+func (l LatestExtremes) NationalExtremes() map[ExtremeType]Extreme {
+	result := map[ExtremeType]Extreme{}
+	for _, region := range l.Regions {
+		for _, e := range region.Extremes {
+			existing, ok := result[e.Type]
+			if !ok || e.Type.IsMoreExtreme(e.Value, existing.Value) {
+				result[e.Type] = e
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package datapoint
+
+import "time"
+
+// firstInt returns the first of the parameters which is present on the forecast. Three hourly forecasts and the day and
+// night segments of daily forecasts report the same measure under different parameters, so accessors list each
+func (f Forecast) firstInt(params ...KnownParameter) (IntParameterValue, bool) {
+	for _, p := range params {
+		v, ok := f.IntParams[string(p)]
+		if ok {
+			return v, true
+		}
+	}
+	return IntParameterValue{}, false
+}
+
+func (f Forecast) firstString(params ...KnownParameter) (StringParameterValue, bool) {
+	for _, p := range params {
+		v, ok := f.StringParams[string(p)]
+		if ok {
+			return v, true
+		}
+	}
+	return StringParameterValue{}, false
+}
+
+// Temperature returns the temperature of a three hourly forecast, the maximum temperature of a daily day forecast or
+// the minimum temperature of a daily night forecast
+func (f Forecast) Temperature() (IntParameterValue, bool) {
+	return f.firstInt(KnownParameterTemperature, KnownParameterDayMaximumTemperature, KnownParameterNightMinimumTemperature)
+}
+
+// FeelsLikeTemperature returns the feels like temperature of a three hourly forecast, or the feels like maximum or
+// minimum temperature of a daily day or night forecast
+func (f Forecast) FeelsLikeTemperature() (IntParameterValue, bool) {
+	return f.firstInt(KnownParameterFeelsLikeTemp, KnownParameterFeelsLikeDayMaximumTemperature, KnownParameterFeelsLikeNightMinimumTemperature)
+}
+
+// WindSpeed returns the wind speed of the forecast
+func (f Forecast) WindSpeed() (IntParameterValue, bool) {
+	return f.firstInt(KnownParameterWindSpeed)
+}
+
+// WindGust returns the wind gust of a three hourly forecast, or the wind gust at noon or midnight of a daily day or
+// night forecast
+func (f Forecast) WindGust() (IntParameterValue, bool) {
+	return f.firstInt(KnownParameterWindGust, KnownParameterWindGustNoon, KnownParameterWindGustMidnight)
+}
+
+// WindDirection returns the 16 point compass direction the wind is blowing from, e.g. 'SSW'
+func (f Forecast) WindDirection() (StringParameterValue, bool) {
+	return f.firstString(KnownParameterWindDirection)
+}
+
+// Humidity returns the screen relative humidity of a three hourly forecast, or the humidity at noon or midnight of a
+// daily day or night forecast
+func (f Forecast) Humidity() (IntParameterValue, bool) {
+	return f.firstInt(KnownParameterScreenRelativeHumidity, KnownParameterScreenRelativeHumidityNoon, KnownParameterScreenRelativeHumidityMidnight)
+}
+
+// Visibility returns the visibility band of the forecast
+func (f Forecast) Visibility() (Visibility, bool) {
+	v, ok := f.firstString(KnownParameterVisibility)
+	if !ok {
+		return VisibilityUnknown, false
+	}
+	return Visibility(v.Value), true
+}
+
+// UvIndex returns the maximum UV index of the forecast. Night forecasts do not include a UV index
+func (f Forecast) UvIndex() (UvIndex, bool) {
+	v, ok := f.firstInt(KnownParameterMaxUvIndex)
+	if !ok {
+		return 0, false
+	}
+	return UvIndex(v.Value), true
+}
+
+// WeatherType returns the significant weather of the forecast. If DataPoint did not provide a weather type this
+// returns WeatherTypeNotAvailable
+func (f Forecast) WeatherType() (WeatherType, bool) {
+	v, ok := f.firstInt(KnownParameterWeatherType)
+	if !ok {
+		return WeatherTypeNotAvailable, false
+	}
+	return WeatherType(v.Value), true
+}
+
+// PrecipitationProbability returns the precipitation probability of a three hourly forecast, or the probability for
+// the day or night of a daily forecast
+func (f Forecast) PrecipitationProbability() (IntParameterValue, bool) {
+	return f.firstInt(KnownParameterPrecipitationProbability, KnownParameterPrecipitationProbabilityDay, KnownParameterPrecipitationProbabilityNight)
+}
+
+// ForecastAt returns the forecast which is valid at the time, that is the one whose ValidFrom is at or before the time
+// and whose ValidTo is after it. The second value is false if no forecast covers the time
+func (s SiteRep) ForecastAt(t time.Time) (Forecast, bool) {
+	for _, period := range s.Location.Period {
+		for _, f := range period.Forecasts {
+			if !f.ValidFrom.After(t) && f.ValidTo.After(t) {
+				return f, true
+			}
+		}
+	}
+	return Forecast{}, false
+}
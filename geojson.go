@@ -0,0 +1,254 @@
+package datapoint
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// GeoJsonGeometry is an RFC 7946 Point geometry. Coordinates are the longitude and latitude in that order
+type GeoJsonGeometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// GeoJsonFeature is an RFC 7946 Feature. Geometry is nil, and is encoded as null, if the position of the feature is not
+// known
+type GeoJsonFeature struct {
+	Type       string           `json:"type"`
+	Id         any              `json:"id,omitempty"`
+	Geometry   *GeoJsonGeometry `json:"geometry"`
+	Properties map[string]any   `json:"properties"`
+}
+
+// GeoJsonFeatureCollection is an RFC 7946 FeatureCollection
+type GeoJsonFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJsonFeature `json:"features"`
+}
+
+func geoJsonPoint(position LatLon) *GeoJsonGeometry {
+	return &GeoJsonGeometry{Type: "Point", Coordinates: []float64{position.Longitude, position.Latitude}}
+}
+
+func newGeoJsonFeatureCollection(features []GeoJsonFeature) GeoJsonFeatureCollection {
+	if features == nil {
+		features = []GeoJsonFeature{}
+	}
+	return GeoJsonFeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+// SiteFeature converts a site into a Point feature with its details as properties
+func SiteFeature(site Site) GeoJsonFeature {
+	return GeoJsonFeature{
+		Type:     "Feature",
+		Id:       site.Id,
+		Geometry: geoJsonPoint(site.LatLon()),
+		Properties: map[string]any{
+			"id":              site.Id,
+			"name":            site.Name,
+			"elevation":       site.Elevation,
+			"region":          site.Region,
+			"unitaryAuthArea": site.UnitaryAuthArea,
+		},
+	}
+}
+
+// SitesFeatureCollection converts a site list into a FeatureCollection with one feature per site
+func SitesFeatureCollection(sites []Site) GeoJsonFeatureCollection {
+	features := make([]GeoJsonFeature, len(sites))
+	for i, site := range sites {
+		features[i] = SiteFeature(site)
+	}
+	return newGeoJsonFeatureCollection(features)
+}
+
+// SiteRepFeature converts the forecast for a site which is valid at the time into a Point feature. The properties
+// contain the details of the site, the period the forecast is valid for and the value of every parameter keyed by its
+// name. The second value is false if no forecast is valid at the time
+func SiteRepFeature(rep SiteRep, at time.Time) (GeoJsonFeature, bool) {
+	f, ok := rep.ForecastAt(at)
+	if !ok {
+		return GeoJsonFeature{}, false
+	}
+
+	properties := map[string]any{
+		"id":        rep.Location.Id,
+		"name":      rep.Location.Name,
+		"elevation": rep.Location.Elevation,
+		"dataDate":  rep.DataDate.Format(time.RFC3339),
+		"validFrom": f.ValidFrom.Format(time.RFC3339),
+		"validTo":   f.ValidTo.Format(time.RFC3339),
+	}
+	if f.Segment != SegmentNone {
+		properties["segment"] = string(f.Segment)
+	}
+	for name, p := range f.StringParams {
+		properties[name] = p.Value
+	}
+	for name, p := range f.FloatParams {
+		properties[name] = p.Value
+	}
+	for name, p := range f.IntParams {
+		properties[name] = p.Value
+	}
+	if w, ok := f.WeatherType(); ok {
+		properties["weatherDescription"] = w.String()
+	}
+
+	return GeoJsonFeature{
+		Type:       "Feature",
+		Id:         rep.Location.Id,
+		Geometry:   geoJsonPoint(LatLon{Latitude: rep.Location.Latitude, Longitude: rep.Location.Longitude}),
+		Properties: properties,
+	}, true
+}
+
+// SiteRepsFeatureCollection converts the forecasts valid at the time into a FeatureCollection with one feature per
+// site. Sites without a forecast at the time are left out
+func SiteRepsFeatureCollection(reps []SiteRep, at time.Time) GeoJsonFeatureCollection {
+	var features []GeoJsonFeature
+	for _, rep := range reps {
+		if feature, ok := SiteRepFeature(rep, at); ok {
+			features = append(features, feature)
+		}
+	}
+	return newGeoJsonFeatureCollection(features)
+}
+
+// ExtremesFeatureCollection converts the latest extremes into a FeatureCollection with one feature per extreme. The
+// position of each extreme is taken from the site with a matching ID, which should come from ObservationSiteList.
+// Extremes without a matching site have a null geometry
+func ExtremesFeatureCollection(extremes LatestExtremes, sites []Site) GeoJsonFeatureCollection {
+	byId := make(map[int]Site, len(sites))
+	for _, site := range sites {
+		byId[site.Id] = site
+	}
+
+	var features []GeoJsonFeature
+	for _, region := range extremes.Regions {
+		for _, e := range region.Extremes {
+			feature := GeoJsonFeature{
+				Type: "Feature",
+				Properties: map[string]any{
+					"regionId":     region.Id,
+					"regionName":   region.Name,
+					"locationId":   e.LocationId,
+					"locationName": e.LocationName,
+					"type":         string(e.Type),
+					"description":  e.Type.String(),
+					"value":        e.Value,
+					"uom":          e.UnitOfMeasurement,
+					"extremeDate":  extremes.ExtremeDate.Format(time.DateOnly),
+				},
+			}
+			if site, ok := byId[e.LocationId]; ok {
+				feature.Geometry = geoJsonPoint(site.LatLon())
+			}
+			features = append(features, feature)
+		}
+	}
+	return newGeoJsonFeatureCollection(features)
+}
+
+// GeoJsonEncoder writes a FeatureCollection one feature at a time so that large collections, such as the forecasts for
+// every site, never have to be held in memory as GeoJSON. Close must be called to finish the collection
+type GeoJsonEncoder struct {
+	w       *bufio.Writer
+	started bool
+	closed  bool
+}
+
+// NewGeoJsonEncoder returns an encoder which writes a FeatureCollection to w
+func NewGeoJsonEncoder(w io.Writer) *GeoJsonEncoder {
+	return &GeoJsonEncoder{w: bufio.NewWriter(w)}
+}
+
+// Encode writes a feature to the collection
+func (e *GeoJsonEncoder) Encode(feature GeoJsonFeature) error {
+	if e.closed {
+		return errors.New("cannot encode a feature after the encoder is closed")
+	}
+
+	data, err := json.Marshal(feature)
+	if err != nil {
+		return fmt.Errorf("failed to encode feature: %w", err)
+	}
+
+	prefix := ","
+	if !e.started {
+		prefix = `{"type":"FeatureCollection","features":[`
+		e.started = true
+	}
+	if _, err := e.w.WriteString(prefix); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+// Close finishes the collection and flushes it to the underlying writer. It does not close the writer
+func (e *GeoJsonEncoder) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+
+	suffix := "]}\n"
+	if !e.started {
+		suffix = `{"type":"FeatureCollection","features":[]}` + "\n"
+	}
+	if _, err := e.w.WriteString(suffix); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+// WriteSitesGeoJson streams the sites to w as a FeatureCollection
+func WriteSitesGeoJson(w io.Writer, sites []Site) error {
+	encoder := NewGeoJsonEncoder(w)
+	for _, site := range sites {
+		if err := encoder.Encode(SiteFeature(site)); err != nil {
+			return err
+		}
+	}
+	return encoder.Close()
+}
+
+// WriteSiteRepsGeoJson streams the forecasts valid at the time to w as a FeatureCollection. Sites without a forecast
+// at the time are left out. The forecasts must already be decoded, WriteForecastGeoJson also streams the response
+func WriteSiteRepsGeoJson(w io.Writer, reps []SiteRep, at time.Time) error {
+	encoder := NewGeoJsonEncoder(w)
+	for _, rep := range reps {
+		feature, ok := SiteRepFeature(rep, at)
+		if !ok {
+			continue
+		}
+		if err := encoder.Encode(feature); err != nil {
+			return err
+		}
+	}
+	return encoder.Close()
+}
+
+// WriteForecastGeoJson streams the forecast for every site at the time to w as a FeatureCollection. Each location is
+// decoded from the response and written as soon as it is read using FiveDayForecastForAllLocationsFunc, so the all
+// locations feed can be exported without holding it in memory. Three hourly forecasts only request the time step
+// covering the time, while daily forecasts request every time step
+func (d *DataPointClient) WriteForecastGeoJson(w io.Writer, resolution Resolution, at time.Time) error {
+	encoder := NewGeoJsonEncoder(w)
+	err := d.FiveDayForecastForAllLocationsFunc(resolution, resolution.timeStep(at), func(rep SiteRep) error {
+		feature, ok := SiteRepFeature(rep, at)
+		if !ok {
+			return nil
+		}
+		return encoder.Encode(feature)
+	})
+	if err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,131 @@
+package datapoint
+
+import (
+	"embed"
+	"encoding/base64"
+	"io/fs"
+)
+
+//go:embed icons/*.svg
+var iconFS embed.FS
+
+// IconVariant selects between the day and night versions of a weather icon
+type IconVariant string
+
+const (
+	IconVariantDay   IconVariant = "day"
+	IconVariantNight IconVariant = "night"
+)
+
+// iconNotAvailable is used for WeatherTypeNotAvailable, WeatherTypeNotUsed and any unknown weather type
+const iconNotAvailable = "not-available"
+
+// weatherIcons maps each weather type onto the base name of its icon. Day and night variants of a weather type share
+// an icon, with the variant being selected separately
+var weatherIcons = map[WeatherType]string{
+	WeatherTypeClearNight:           "clear",
+	WeatherTypeSunnyDay:             "clear",
+	WeatherTypePartlyCloudyNight:    "partly-cloudy",
+	WeatherTypePartlyCloudyDay:      "partly-cloudy",
+	WeatherTypeMist:                 "mist",
+	WeatherTypeFog:                  "fog",
+	WeatherTypeCloudy:               "cloudy",
+	WeatherTypeOvercast:             "overcast",
+	WeatherTypeLightRainShowerNight: "light-rain-shower",
+	WeatherTypeLightRainShowerDay:   "light-rain-shower",
+	WeatherTypeDrizzle:              "drizzle",
+	WeatherTypeLightRain:            "light-rain",
+	WeatherTypeHeavyRainShowerNight: "heavy-rain-shower",
+	WeatherTypeHeavyRainShowerDay:   "heavy-rain-shower",
+	WeatherTypeHeavyRain:            "heavy-rain",
+	WeatherTypeSleetShowerNight:     "sleet-shower",
+	WeatherTypeSleetShowerDay:       "sleet-shower",
+	WeatherTypeSleet:                "sleet",
+	WeatherTypeHailShowerNight:      "hail-shower",
+	WeatherTypeHailShowerDay:        "hail-shower",
+	WeatherTypeHail:                 "hail",
+	WeatherTypeLightSnowShowerNight: "light-snow-shower",
+	WeatherTypeLightSnowShowerDay:   "light-snow-shower",
+	WeatherTypeLightSnow:            "light-snow",
+	WeatherTypeHeavySnowShowerNight: "heavy-snow-shower",
+	WeatherTypeHeavySnowShowerDay:   "heavy-snow-shower",
+	WeatherTypeHeavySnow:            "heavy-snow",
+	WeatherTypeThunderShowerNight:   "thunder-shower",
+	WeatherTypeThunderShowerDay:     "thunder-shower",
+	WeatherTypeThunder:              "thunder",
+}
+
+// IconName returns the file name of the icon for this weather type within the embedded icon set
+func (w WeatherType) IconName(variant IconVariant) string {
+	name, ok := weatherIcons[w]
+	if !ok {
+		return iconNotAvailable + ".svg"
+	}
+	if variant != IconVariantNight {
+		variant = IconVariantDay
+	}
+	return name + "-" + string(variant) + ".svg"
+}
+
+// Icon returns the SVG icon for this weather type. Weather types which are not available or unknown return a
+// placeholder icon
+func (w WeatherType) Icon(variant IconVariant) []byte {
+	icon, err := iconFS.ReadFile("icons/" + w.IconName(variant))
+	if err != nil {
+		// every name returned from IconName is embedded so this cannot happen outside of development
+		panic(err)
+	}
+	return icon
+}
+
+// IconDataURI returns the SVG icon for this weather type as a base64 encoded data URI which can be used directly as
+// the source of an image
+func (w WeatherType) IconDataURI(variant IconVariant) string {
+	return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(w.Icon(variant))
+}
+
+// IconVariant returns the variant of icon which should be used for this forecast. Weather types with separate day and
+// night codes use the variant of their code, otherwise the segment of a daily forecast or the local time of the
+// forecast is used
+func (f Forecast) IconVariant() IconVariant {
+	w, _ := f.WeatherType()
+	switch {
+	case w.IsNight():
+		return IconVariantNight
+	case w.IsDay():
+		return IconVariantDay
+	case f.Segment == SegmentNight:
+		return IconVariantNight
+	case f.Segment == SegmentDay:
+		return IconVariantDay
+	}
+
+	hour := f.Time.In(London).Hour()
+	if hour >= dayStartHour && hour < nightStartHour {
+		return IconVariantDay
+	}
+	return IconVariantNight
+}
+
+// Icon returns the SVG icon for the weather type of this forecast, or a placeholder if the forecast has no weather
+// type
+func (f Forecast) Icon() []byte {
+	w, _ := f.WeatherType()
+	return w.Icon(f.IconVariant())
+}
+
+// IconDataURI returns the SVG icon for the weather type of this forecast as a base64 encoded data URI
+func (f Forecast) IconDataURI() string {
+	w, _ := f.WeatherType()
+	return w.IconDataURI(f.IconVariant())
+}
+
+// Icons returns the embedded icon set so it can be served directly, for example with http.FS. Files are named as
+// returned from WeatherType.IconName
+func Icons() fs.FS {
+	icons, err := fs.Sub(iconFS, "icons")
+	if err != nil {
+		panic(err)
+	}
+	return icons
+}
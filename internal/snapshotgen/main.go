@@ -0,0 +1,59 @@
+// Command snapshotgen refreshes the site list snapshots embedded in the datapoint package. It is run by go generate and
+// requires an API key in the DATAPOINT_API_KEY environment variable
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	dp "github.com/vitineth/datapoint"
+)
+
+func main() {
+	out := flag.String("out", "snapshot", "directory to write the snapshots to")
+	flag.Parse()
+
+	key := os.Getenv("DATAPOINT_API_KEY")
+	if key == "" {
+		fmt.Fprintln(os.Stderr, "DATAPOINT_API_KEY must be set to refresh the site list snapshots")
+		os.Exit(1)
+	}
+
+	client, err := dp.NewClient(dp.WithApiKey(key))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create client: %v\n", err)
+		os.Exit(1)
+	}
+
+	lists := []struct {
+		file  string
+		fetch func() ([]dp.Site, error)
+	}{
+		{dp.ForecastSnapshotFile, client.ForecastSiteList},
+		{dp.ObservationSnapshotFile, client.ObservationSiteList},
+	}
+	for _, list := range lists {
+		sites, err := list.fetch()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to fetch sites for %v: %v\n", list.file, err)
+			os.Exit(1)
+		}
+		if err := write(filepath.Join(*out, list.file), sites); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write %v: %v\n", list.file, err)
+			os.Exit(1)
+		}
+		fmt.Printf("wrote %v sites to %v\n", len(sites), list.file)
+	}
+}
+
+func write(path string, sites []dp.Site) error {
+	data, err := json.MarshalIndent(dp.SiteSnapshot{GeneratedAt: time.Now().UTC().Truncate(time.Second), Sites: sites}, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,387 @@
+package datapoint
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// DefaultLapseRate is the standard environmental lapse rate in °C per metre, the rate at which air temperature falls
+// with height
+const DefaultLapseRate = 0.0065
+
+// InterpolationOptions controls how forecasts from nearby sites are blended by Interpolate
+type InterpolationOptions struct {
+	// Elevation is the height in metres of the position being estimated. If nil the weighted elevation of the sources
+	// is used, which means no lapse rate correction is applied
+	Elevation *float64
+	// Power is the exponent applied to distances when weighting sources. Larger values favour nearer sites. Defaults
+	// to 2
+	Power float64
+	// LapseRate is the fall in temperature in °C for each metre of height used to correct temperatures between the
+	// elevation of a source and the position. Defaults to DefaultLapseRate, a negative value disables the correction
+	LapseRate float64
+	// Sites is the number of nearest sites fetched by DataPointClient.FiveDayForecastAt. Defaults to 4
+	Sites int
+	// MaxDistance is the distance in metres beyond which sources are ignored, or 0 for no limit
+	MaxDistance float64
+}
+
+func (o InterpolationOptions) power() float64 {
+	if o.Power <= 0 {
+		return 2
+	}
+	return o.Power
+}
+
+func (o InterpolationOptions) lapseRate() float64 {
+	switch {
+	case o.LapseRate < 0:
+		return 0
+	case o.LapseRate == 0:
+		return DefaultLapseRate
+	default:
+		return o.LapseRate
+	}
+}
+
+func (o InterpolationOptions) sites() int {
+	if o.Sites <= 0 {
+		return 4
+	}
+	return o.Sites
+}
+
+// InterpolationSource describes one of the sites which contributed to an interpolated forecast
+type InterpolationSource struct {
+	// Id is the ID of the site
+	Id int
+	// Name is the name of the site
+	Name string
+	// Distance is the great-circle distance in metres from the site to the interpolated position
+	Distance float64
+	// Elevation is the elevation of the site in metres
+	Elevation float64
+	// Weight is the share of the site in the interpolated values, the weights of all sources sum to 1
+	Weight float64
+}
+
+// InterpolatedForecast is a forecast for a position estimated from the forecasts of nearby sites
+type InterpolatedForecast struct {
+	SiteRep
+	// Sources contains the sites which were combined to produce the forecast, nearest first
+	Sources []InterpolationSource
+	// SkippedSources contains the IDs of the sites chosen by FiveDayForecastAt for which the service had no forecast
+	SkippedSources []int
+}
+
+// coincidentDistance is the distance in metres within which a source is treated as being at the interpolated
+// position, in which case it is used alone
+const coincidentDistance = 1.0
+
+type weightedRep struct {
+	rep    SiteRep
+	source InterpolationSource
+}
+
+// Interpolate estimates the forecast at a position from the forecasts of nearby sites, which must all share a
+// resolution. Sources are weighted by inverse distance and temperatures are corrected for the difference in elevation
+// between each source and the position using the lapse rate. Numeric parameters are averaged, wind directions are
+// averaged as vectors and other parameters such as the weather type take the value with the greatest total weight.
+//
+// The returned forecast has the time steps of the nearest source, an Id of 0 and lists the contributing sites in
+// Sources
+func Interpolate(position Position, reps []SiteRep, options InterpolationOptions) (*InterpolatedForecast, error) {
+	if len(reps) == 0 {
+		return nil, errors.New("at least one forecast is required to interpolate")
+	}
+
+	target := position.WGS84()
+	var sources []weightedRep
+	for _, rep := range reps {
+		if rep.Resolution != reps[0].Resolution {
+			return nil, fmt.Errorf("cannot interpolate between %v and %v forecasts", reps[0].Resolution, rep.Resolution)
+		}
+		location := LatLon{Latitude: rep.Location.Latitude, Longitude: rep.Location.Longitude}
+		distance := Distance(location, target)
+		if options.MaxDistance > 0 && distance > options.MaxDistance {
+			continue
+		}
+		sources = append(sources, weightedRep{rep: rep, source: InterpolationSource{
+			Id:        rep.Location.Id,
+			Name:      rep.Location.Name,
+			Distance:  distance,
+			Elevation: rep.Location.Elevation,
+		}})
+	}
+	if len(sources) == 0 {
+		return nil, errors.New("no forecasts within the maximum distance to interpolate")
+	}
+
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].source.Distance < sources[j].source.Distance
+	})
+	if sources[0].source.Distance <= coincidentDistance {
+		sources = sources[:1]
+	}
+
+	total := 0.0
+	for i := range sources {
+		sources[i].source.Weight = 1 / math.Pow(max(sources[i].source.Distance, coincidentDistance), options.power())
+		total += sources[i].source.Weight
+	}
+	elevation := 0.0
+	for i := range sources {
+		sources[i].source.Weight /= total
+		elevation += sources[i].source.Weight * sources[i].source.Elevation
+	}
+	if options.Elevation != nil {
+		elevation = *options.Elevation
+	}
+
+	// forecasts from each source are matched on the start and segment of the period they are valid for
+	type forecastKey struct {
+		from    time.Time
+		segment Segment
+	}
+	lookup := make([]map[forecastKey]Forecast, len(sources))
+	for i, s := range sources {
+		lookup[i] = map[forecastKey]Forecast{}
+		for _, period := range s.rep.Location.Period {
+			for _, f := range period.Forecasts {
+				lookup[i][forecastKey{from: f.ValidFrom.UTC(), segment: f.Segment}] = f
+			}
+		}
+	}
+
+	nearest := sources[0].rep
+	periods := make([]Period, len(nearest.Location.Period))
+	for i, period := range nearest.Location.Period {
+		forecasts := make([]Forecast, len(period.Forecasts))
+		for j, f := range period.Forecasts {
+			key := forecastKey{from: f.ValidFrom.UTC(), segment: f.Segment}
+			var matches []weightedForecast
+			for k, s := range sources {
+				if match, ok := lookup[k][key]; ok {
+					matches = append(matches, weightedForecast{forecast: match, weight: s.source.Weight, elevation: s.source.Elevation})
+				}
+			}
+			forecasts[j] = blendForecasts(f, matches, elevation, options.lapseRate())
+		}
+		periods[i] = Period{Type: period.Type, Time: period.Time, Forecasts: forecasts}
+	}
+
+	result := make([]InterpolationSource, len(sources))
+	for i, s := range sources {
+		result[i] = s.source
+	}
+
+	return &InterpolatedForecast{
+		SiteRep: SiteRep{
+			DataDate:   nearest.DataDate,
+			Type:       nearest.Type,
+			Resolution: nearest.Resolution,
+			Location: LocationRep{
+				Latitude:  target.Latitude,
+				Longitude: target.Longitude,
+				Country:   nearest.Location.Country,
+				Continent: nearest.Location.Continent,
+				Elevation: elevation,
+				Period:    periods,
+			},
+		},
+		Sources: result,
+	}, nil
+}
+
+type weightedForecast struct {
+	forecast  Forecast
+	weight    float64
+	elevation float64
+}
+
+// blendForecasts combines the matching forecasts of each source into one, using template for the time of the result
+func blendForecasts(template Forecast, matches []weightedForecast, elevation float64, lapseRate float64) Forecast {
+	result := Forecast{
+		Time:         template.Time,
+		Segment:      template.Segment,
+		ValidFrom:    template.ValidFrom,
+		ValidTo:      template.ValidTo,
+		IntParams:    map[string]IntParameterValue{},
+		FloatParams:  map[string]FloatParameterValue{},
+		StringParams: map[string]StringParameterValue{},
+	}
+
+	for _, m := range matches {
+		for name, p := range m.forecast.IntParams {
+			if _, ok := result.IntParams[name]; ok {
+				continue
+			}
+			if name == string(KnownParameterWeatherType) {
+				value := weightedMode(matches, func(f Forecast) (string, bool) {
+					v, ok := f.IntParams[name]
+					return fmt.Sprint(v.Value), ok
+				})
+				w, _ := ParseWeatherType(value)
+				result.IntParams[name] = IntParameterValue{ParameterDescriptor: p.ParameterDescriptor, Value: int(w)}
+				continue
+			}
+			value := weightedMean(matches, p.ParameterDescriptor, elevation, lapseRate, func(f Forecast) (float64, bool) {
+				v, ok := f.IntParams[name]
+				return float64(v.Value), ok
+			})
+			result.IntParams[name] = IntParameterValue{ParameterDescriptor: p.ParameterDescriptor, Value: int(math.Round(value))}
+		}
+
+		for name, p := range m.forecast.FloatParams {
+			if _, ok := result.FloatParams[name]; ok {
+				continue
+			}
+			value := weightedMean(matches, p.ParameterDescriptor, elevation, lapseRate, func(f Forecast) (float64, bool) {
+				v, ok := f.FloatParams[name]
+				return v.Value, ok
+			})
+			result.FloatParams[name] = FloatParameterValue{ParameterDescriptor: p.ParameterDescriptor, Value: value}
+		}
+
+		for name, p := range m.forecast.StringParams {
+			if _, ok := result.StringParams[name]; ok {
+				continue
+			}
+			get := func(f Forecast) (string, bool) {
+				v, ok := f.StringParams[name]
+				return v.Value, ok
+			}
+			value, ok := "", false
+			if unit, err := p.Unit(); err == nil && unit == UnitCompass {
+				value, ok = weightedDirection(matches, get)
+			}
+			if !ok {
+				value = weightedMode(matches, get)
+			}
+			result.StringParams[name] = StringParameterValue{ParameterDescriptor: p.ParameterDescriptor, Value: value}
+		}
+	}
+	return result
+}
+
+// weightedMean returns the weighted average of a numeric parameter, renormalising the weights over the sources which
+// have it. Temperatures are first corrected to the target elevation
+func weightedMean(matches []weightedForecast, descriptor ParameterDescriptor, elevation float64, lapseRate float64, get func(Forecast) (float64, bool)) float64 {
+	unit, err := descriptor.Unit()
+	temperature := err == nil && unit.Quantity() == QuantityTemperature
+	if unit == UnitFahrenheit {
+		// the lapse rate is per degree Celsius
+		lapseRate *= 9.0 / 5.0
+	}
+
+	sum, total := 0.0, 0.0
+	for _, m := range matches {
+		v, ok := get(m.forecast)
+		if !ok {
+			continue
+		}
+		if temperature {
+			v += lapseRate * (m.elevation - elevation)
+		}
+		sum += v * m.weight
+		total += m.weight
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// weightedMode returns the value of a parameter with the greatest total weight across the sources. Ties are won by the
+// value of the nearest source
+func weightedMode(matches []weightedForecast, get func(Forecast) (string, bool)) string {
+	totals := map[string]float64{}
+	var order []string
+	for _, m := range matches {
+		v, ok := get(m.forecast)
+		if !ok {
+			continue
+		}
+		if _, seen := totals[v]; !seen {
+			order = append(order, v)
+		}
+		totals[v] += m.weight
+	}
+
+	best := ""
+	for _, v := range order {
+		if best == "" || totals[v] > totals[best] {
+			best = v
+		}
+	}
+	return best
+}
+
+// weightedDirection returns the compass point of the weighted vector average of the directions, or false if none of
+// the sources has a direction or they cancel out
+func weightedDirection(matches []weightedForecast, get func(Forecast) (string, bool)) (string, bool) {
+	x, y := 0.0, 0.0
+	for _, m := range matches {
+		v, ok := get(m.forecast)
+		if !ok {
+			continue
+		}
+		point, err := ParseCompassPoint(v)
+		if err != nil {
+			continue
+		}
+		radians, ok := point.Radians()
+		if !ok {
+			continue
+		}
+		x += m.weight * math.Sin(radians)
+		y += m.weight * math.Cos(radians)
+	}
+	if math.Hypot(x, y) < 1e-9 {
+		return "", false
+	}
+	return CompassPointFromDegrees(math.Atan2(x, y) * 180 / math.Pi).String(), true
+}
+
+// FiveDayForecastAt estimates the forecast at any position by fetching the forecasts of the nearest sites in the
+// index and combining them with Interpolate. Sites for which the service has no forecast, such as sites which have
+// been retired since the index was built, are left out and listed in SkippedSources
+func (d *DataPointClient) FiveDayForecastAt(resolution Resolution, position Position, at *time.Time, index *SpatialIndex, options InterpolationOptions) (*InterpolatedForecast, error) {
+	if index == nil {
+		return nil, errors.New("no spatial index provided to find sites to interpolate from")
+	}
+	nearest := index.NearestK(position, options.sites())
+	if len(nearest) == 0 {
+		return nil, errors.New("no sites available to interpolate from")
+	}
+
+	reps := make([]SiteRep, 0, len(nearest))
+	var skipped []int
+	for _, n := range nearest {
+		if options.MaxDistance > 0 && n.Distance > options.MaxDistance {
+			continue
+		}
+		rep, err := d.FiveDayForecast(resolution, n.Site.Id, at)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch forecast for site %v: %w", n.Site.Id, err)
+		}
+		if rep == nil {
+			skipped = append(skipped, n.Site.Id)
+			continue
+		}
+		reps = append(reps, *rep)
+	}
+	if len(reps) == 0 && len(skipped) > 0 {
+		return nil, fmt.Errorf("no forecasts available to interpolate from, sites %v have no forecast", skipped)
+	}
+
+	forecast, err := Interpolate(position, reps, options)
+	if err != nil {
+		return nil, err
+	}
+	forecast.SkippedSources = skipped
+	return forecast, nil
+}
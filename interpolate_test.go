@@ -0,0 +1,172 @@
+package datapoint_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	dp "github.com/vitineth/datapoint"
+	"github.com/vitineth/datapoint/datapointtest"
+)
+
+var interpolationTime = time.Date(2024, time.March, 14, 12, 0, 0, 0, time.UTC)
+
+// sourceRep returns a three hourly forecast for a site with a single time step holding the values
+func sourceRep(id int, position dp.LatLon, elevation float64, temperature int, dewPoint float64, direction string) dp.SiteRep {
+	return dp.SiteRep{
+		Resolution: dp.ResolutionThreeHourly,
+		Location: dp.LocationRep{
+			Id:        id,
+			Latitude:  position.Latitude,
+			Longitude: position.Longitude,
+			Elevation: elevation,
+			Period: []dp.Period{{Time: interpolationTime, Forecasts: []dp.Forecast{{
+				Time:      interpolationTime,
+				ValidFrom: interpolationTime,
+				ValidTo:   interpolationTime.Add(3 * time.Hour),
+				IntParams: map[string]dp.IntParameterValue{
+					"T": {ParameterDescriptor: dp.ParameterDescriptor{Name: "T", Units: "C"}, Value: temperature},
+				},
+				FloatParams: map[string]dp.FloatParameterValue{
+					"Dp": {ParameterDescriptor: dp.ParameterDescriptor{Name: "Dp", Units: "C"}, Value: dewPoint},
+				},
+				StringParams: map[string]dp.StringParameterValue{
+					"D": {ParameterDescriptor: dp.ParameterDescriptor{Name: "D", Units: "compass"}, Value: direction},
+				},
+			}}}},
+		},
+	}
+}
+
+func interpolatedForecast(t *testing.T, forecast *dp.InterpolatedForecast) dp.Forecast {
+	t.Helper()
+	if len(forecast.Location.Period) != 1 || len(forecast.Location.Period[0].Forecasts) != 1 {
+		t.Fatalf("expected a single interpolated forecast but got %+v", forecast.Location.Period)
+	}
+	return forecast.Location.Period[0].Forecasts[0]
+}
+
+func TestInterpolateWeights(t *testing.T) {
+	target := dp.LatLon{Latitude: 51, Longitude: -2}
+	near := sourceRep(1, dp.LatLon{Latitude: 51.1, Longitude: -2}, 0, 10, 4, "NNW")
+	far := sourceRep(2, dp.LatLon{Latitude: 50.8, Longitude: -2}, 0, 20, 10, "NNE")
+	noLapse := dp.InterpolationOptions{LapseRate: -1}
+
+	forecast, err := dp.Interpolate(target, []dp.SiteRep{far, near}, noLapse)
+	if err != nil {
+		t.Fatalf("failed to interpolate: %v", err)
+	}
+	if len(forecast.Sources) != 2 || forecast.Sources[0].Id != 1 || forecast.Sources[1].Id != 2 {
+		t.Fatalf("expected both sources nearest first but got %+v", forecast.Sources)
+	}
+
+	// the far site is twice as far away so has a quarter of the inverse square weight of the near site
+	near1, far1 := forecast.Sources[0], forecast.Sources[1]
+	if ratio := far1.Distance / near1.Distance; math.Abs(ratio-2) > 1e-3 {
+		t.Fatalf("expected the far site to be twice as far as the near site but the ratio was %v", ratio)
+	}
+	if math.Abs(near1.Weight-0.8) > 1e-3 || math.Abs(far1.Weight-0.2) > 1e-3 {
+		t.Errorf("expected weights of 0.8 and 0.2 but got %v and %v", near1.Weight, far1.Weight)
+	}
+
+	f := interpolatedForecast(t, forecast)
+	expected := near1.Weight*4 + far1.Weight*10
+	if dew := f.FloatParams["Dp"].Value; math.Abs(dew-expected) > 1e-9 {
+		t.Errorf("expected a weighted dew point of %v but got %v", expected, dew)
+	}
+	if temperature := f.IntParams["T"].Value; temperature != 12 {
+		t.Errorf("expected a weighted temperature of 12 but got %v", temperature)
+	}
+	// directions are averaged as vectors, so NNW and NNE meet near north rather than at the arithmetic mean of south
+	if direction := f.StringParams["D"].Value; direction != "NNW" {
+		t.Errorf("expected NNW and NNE to average to NNW but got %v", direction)
+	}
+
+	// a power of 1 weights by inverse distance, giving the near site two thirds of the weight
+	forecast, err = dp.Interpolate(target, []dp.SiteRep{far, near}, dp.InterpolationOptions{LapseRate: -1, Power: 1})
+	if err != nil {
+		t.Fatalf("failed to interpolate: %v", err)
+	}
+	if w := forecast.Sources[0].Weight; math.Abs(w-2.0/3.0) > 1e-3 {
+		t.Errorf("expected a weight of 2/3 with a power of 1 but got %v", w)
+	}
+
+	// sources beyond the maximum distance are left out
+	forecast, err = dp.Interpolate(target, []dp.SiteRep{far, near}, dp.InterpolationOptions{LapseRate: -1, MaxDistance: near1.Distance + 1})
+	if err != nil {
+		t.Fatalf("failed to interpolate: %v", err)
+	}
+	if len(forecast.Sources) != 1 || forecast.Sources[0].Id != 1 || forecast.Sources[0].Weight != 1 {
+		t.Errorf("expected only the near site within the maximum distance but got %+v", forecast.Sources)
+	}
+
+	// a source at the position is used alone
+	forecast, err = dp.Interpolate(dp.LatLon{Latitude: 51.1, Longitude: -2}, []dp.SiteRep{far, near}, noLapse)
+	if err != nil {
+		t.Fatalf("failed to interpolate: %v", err)
+	}
+	if len(forecast.Sources) != 1 || interpolatedForecast(t, forecast).IntParams["T"].Value != 10 {
+		t.Errorf("expected a coincident site to be used alone but got %+v", forecast.Sources)
+	}
+}
+
+func TestInterpolateLapseRate(t *testing.T) {
+	position := dp.LatLon{Latitude: 51, Longitude: -2}
+	source := sourceRep(1, position, 100, 20, 10, "N")
+	elevation := 600.0
+
+	tests := []struct {
+		name     string
+		options  dp.InterpolationOptions
+		expected float64
+	}{
+		{"default lapse rate", dp.InterpolationOptions{Elevation: &elevation}, 10 - 500*dp.DefaultLapseRate},
+		{"custom lapse rate", dp.InterpolationOptions{Elevation: &elevation, LapseRate: 0.01}, 5},
+		{"disabled", dp.InterpolationOptions{Elevation: &elevation, LapseRate: -1}, 10},
+		{"source elevation", dp.InterpolationOptions{}, 10},
+	}
+	for _, test := range tests {
+		forecast, err := dp.Interpolate(position, []dp.SiteRep{source}, test.options)
+		if err != nil {
+			t.Fatalf("%v: failed to interpolate: %v", test.name, err)
+		}
+		f := interpolatedForecast(t, forecast)
+		if dew := f.FloatParams["Dp"].Value; math.Abs(dew-test.expected) > 1e-9 {
+			t.Errorf("%v: expected a dew point of %v but got %v", test.name, test.expected, dew)
+		}
+		if temperature := f.IntParams["T"].Value; temperature != int(math.Round(test.expected+10)) {
+			t.Errorf("%v: expected a temperature of %v but got %v", test.name, math.Round(test.expected+10), temperature)
+		}
+	}
+}
+
+func TestFiveDayForecastAt(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	exeter := dp.LatLon{Latitude: 50.7, Longitude: -3.5}
+
+	if _, err := client.FiveDayForecastAt(dp.ResolutionThreeHourly, exeter, nil, nil, dp.InterpolationOptions{}); err == nil {
+		t.Error("expected an error without a spatial index")
+	}
+
+	// a site which the service has no forecast for is skipped
+	retired := dp.Site{Id: 1, Latitude: 50.71, Longitude: -3.51, Name: "Retired"}
+	index := dp.NewSpatialIndex(append(datapointtest.DefaultForecastSites(), retired))
+	forecast, err := client.FiveDayForecastAt(dp.ResolutionThreeHourly, exeter, nil, index, dp.InterpolationOptions{Sites: 3})
+	if err != nil {
+		t.Fatalf("failed to interpolate: %v", err)
+	}
+	if len(forecast.SkippedSources) != 1 || forecast.SkippedSources[0] != retired.Id {
+		t.Errorf("expected the retired site to be skipped but got %v", forecast.SkippedSources)
+	}
+	if len(forecast.Sources) != 2 || forecast.Sources[0].Id != 310069 {
+		t.Errorf("expected Exeter and one other site as sources but got %+v", forecast.Sources)
+	}
+	if len(forecast.Location.Period) == 0 {
+		t.Error("expected the interpolated forecast to have periods")
+	}
+}
@@ -0,0 +1,261 @@
+package datapoint
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Language identifies the language of a Catalogue, using BCP 47 tags such as 'en' or 'cy'
+type Language string
+
+const (
+	LanguageEnglish Language = "en"
+	LanguageWelsh   Language = "cy"
+)
+
+// base returns the primary language of a tag, e.g. 'cy' for 'cy-GB'
+func (l Language) base() Language {
+	tag, _, _ := strings.Cut(string(l), "-")
+	return Language(strings.ToLower(tag))
+}
+
+// Catalogue maps message keys onto the text shown to users in a single language. Keys are formed from the kind of
+// value and its DataPoint code, for example 'weather.12', 'visibility.VP', 'uv.high', 'compass.SSW' or 'extreme.HMAXT'
+type Catalogue map[string]string
+
+var (
+	cataloguesLock sync.RWMutex
+	catalogues     = map[Language]Catalogue{
+		LanguageEnglish: englishCatalogue,
+		LanguageWelsh:   welshCatalogue,
+	}
+)
+
+// RegisterCatalogue adds the messages in the catalogue to those available for the language. Existing messages with
+// the same keys are replaced, so this can be used to add a new language or to override the wording of a built-in one
+func RegisterCatalogue(language Language, catalogue Catalogue) {
+	cataloguesLock.Lock()
+	defer cataloguesLock.Unlock()
+
+	existing, ok := catalogues[language]
+	merged := make(Catalogue, len(existing)+len(catalogue))
+	if ok {
+		for k, v := range existing {
+			merged[k] = v
+		}
+	}
+	for k, v := range catalogue {
+		merged[k] = v
+	}
+	catalogues[language] = merged
+}
+
+// Translate returns the message for the key in the language. If the language has no message for the key its primary
+// language is tried, followed by English. The second return value is false if no catalogue contains the key
+func Translate(language Language, key string) (string, bool) {
+	cataloguesLock.RLock()
+	defer cataloguesLock.RUnlock()
+
+	for _, l := range []Language{language, language.base(), LanguageEnglish} {
+		if msg, ok := catalogues[l][key]; ok {
+			return msg, true
+		}
+	}
+	return "", false
+}
+
+func translateOr(language Language, key string, fallback string) string {
+	msg, ok := Translate(language, key)
+	if !ok {
+		return fallback
+	}
+	return msg
+}
+
+// Localise returns the description of the weather type in the language
+func (w WeatherType) Localise(language Language) string {
+	return translateOr(language, "weather."+w.Code(), fmt.Sprintf("Unknown (%d)", int(w)))
+}
+
+// Localise returns the name of the visibility band in the language
+func (v Visibility) Localise(language Language) string {
+	return translateOr(language, "visibility."+string(v), string(v))
+}
+
+// LocaliseExposure returns the name of the exposure level of the index in the language, e.g. 'High' or 'Uchel'
+func (i UvIndex) LocaliseExposure(language Language) string {
+	return i.Category().Localise(language)
+}
+
+// LocaliseCompass returns the name of a 16 point compass direction such as 'SSW' in the language
+func LocaliseCompass(direction string, language Language) string {
+	return translateOr(language, "compass."+strings.ToUpper(direction), direction)
+}
+
+// LocaliseExtremeType returns the name of a UK extreme type such as 'HMAXT' in the language
+func LocaliseExtremeType(extremeType string, language Language) string {
+	return translateOr(language, "extreme."+strings.ToUpper(extremeType), extremeType)
+}
+
+var englishCatalogue = Catalogue{
+	"weather.NA": "Not available",
+	"weather.0":  "Clear night",
+	"weather.1":  "Sunny day",
+	"weather.2":  "Partly cloudy (night)",
+	"weather.3":  "Partly cloudy (day)",
+	"weather.4":  "Not used",
+	"weather.5":  "Mist",
+	"weather.6":  "Fog",
+	"weather.7":  "Cloudy",
+	"weather.8":  "Overcast",
+	"weather.9":  "Light rain shower (night)",
+	"weather.10": "Light rain shower (day)",
+	"weather.11": "Drizzle",
+	"weather.12": "Light rain",
+	"weather.13": "Heavy rain shower (night)",
+	"weather.14": "Heavy rain shower (day)",
+	"weather.15": "Heavy rain",
+	"weather.16": "Sleet shower (night)",
+	"weather.17": "Sleet shower (day)",
+	"weather.18": "Sleet",
+	"weather.19": "Hail shower (night)",
+	"weather.20": "Hail shower (day)",
+	"weather.21": "Hail",
+	"weather.22": "Light snow shower (night)",
+	"weather.23": "Light snow shower (day)",
+	"weather.24": "Light snow",
+	"weather.25": "Heavy snow shower (night)",
+	"weather.26": "Heavy snow shower (day)",
+	"weather.27": "Heavy snow",
+	"weather.28": "Thunder shower (night)",
+	"weather.29": "Thunder shower (day)",
+	"weather.30": "Thunder",
+
+	"visibility.UN": "Unknown",
+	"visibility.VP": "Very poor",
+	"visibility.PO": "Poor",
+	"visibility.MO": "Moderate",
+	"visibility.GO": "Good",
+	"visibility.VG": "Very good",
+	"visibility.EX": "Excellent",
+
+	"uv.low":       "Low",
+	"uv.moderate":  "Moderate",
+	"uv.high":      "High",
+	"uv.very-high": "Very high",
+	"uv.extreme":   "Extreme",
+
+	"uv.advice.low":       "No protection needed. You can safely stay outside.",
+	"uv.advice.moderate":  "Protection needed. Seek shade during midday hours, cover up and wear sunscreen and a hat.",
+	"uv.advice.high":      "Protection needed. Seek shade during midday hours, cover up and wear sunscreen and a hat.",
+	"uv.advice.very-high": "Extra protection needed. Avoid being outside during midday hours. Make sure you seek shade. A shirt, sunscreen and a hat are a must.",
+	"uv.advice.extreme":   "Extra protection needed. Avoid being outside during midday hours. Make sure you seek shade. A shirt, sunscreen and a hat are a must.",
+
+	"compass.N":    "North",
+	"compass.NNE":  "North north east",
+	"compass.NE":   "North east",
+	"compass.ENE":  "East north east",
+	"compass.E":    "East",
+	"compass.ESE":  "East south east",
+	"compass.SE":   "South east",
+	"compass.SSE":  "South south east",
+	"compass.S":    "South",
+	"compass.SSW":  "South south west",
+	"compass.SW":   "South west",
+	"compass.WSW":  "West south west",
+	"compass.W":    "West",
+	"compass.WNW":  "West north west",
+	"compass.NW":   "North west",
+	"compass.NNW":  "North north west",
+	"compass.CALM": "Calm",
+	"compass.VRB":  "Variable",
+
+	"extreme.HMAXT": "Highest maximum temperature",
+	"extreme.LMAXT": "Lowest maximum temperature",
+	"extreme.HMINT": "Highest minimum temperature",
+	"extreme.LMINT": "Lowest minimum temperature",
+	"extreme.HRAIN": "Highest rainfall",
+	"extreme.HSUN":  "Highest sunshine",
+}
+
+var welshCatalogue = Catalogue{
+	"weather.NA": "Ddim ar gael",
+	"weather.0":  "Noson glir",
+	"weather.1":  "Diwrnod heulog",
+	"weather.2":  "Rhannol gymylog (nos)",
+	"weather.3":  "Rhannol gymylog (dydd)",
+	"weather.4":  "Heb ei ddefnyddio",
+	"weather.5":  "Tarth",
+	"weather.6":  "Niwl",
+	"weather.7":  "Cymylog",
+	"weather.8":  "Cymylau trwchus",
+	"weather.9":  "Cawod ysgafn o law (nos)",
+	"weather.10": "Cawod ysgafn o law (dydd)",
+	"weather.11": "Glaw mân",
+	"weather.12": "Glaw ysgafn",
+	"weather.13": "Cawod drom o law (nos)",
+	"weather.14": "Cawod drom o law (dydd)",
+	"weather.15": "Glaw trwm",
+	"weather.16": "Cawod o eirlaw (nos)",
+	"weather.17": "Cawod o eirlaw (dydd)",
+	"weather.18": "Eirlaw",
+	"weather.19": "Cawod o genllysg (nos)",
+	"weather.20": "Cawod o genllysg (dydd)",
+	"weather.21": "Cenllysg",
+	"weather.22": "Cawod ysgafn o eira (nos)",
+	"weather.23": "Cawod ysgafn o eira (dydd)",
+	"weather.24": "Eira ysgafn",
+	"weather.25": "Cawod drom o eira (nos)",
+	"weather.26": "Cawod drom o eira (dydd)",
+	"weather.27": "Eira trwm",
+	"weather.28": "Cawod o daranau (nos)",
+	"weather.29": "Cawod o daranau (dydd)",
+	"weather.30": "Taranau",
+
+	"visibility.UN": "Anhysbys",
+	"visibility.VP": "Gwael iawn",
+	"visibility.PO": "Gwael",
+	"visibility.MO": "Cymedrol",
+	"visibility.GO": "Da",
+	"visibility.VG": "Da iawn",
+	"visibility.EX": "Ardderchog",
+
+	"uv.low":       "Isel",
+	"uv.moderate":  "Cymedrol",
+	"uv.high":      "Uchel",
+	"uv.very-high": "Uchel iawn",
+	"uv.extreme":   "Eithafol",
+
+	"uv.advice.low":       "Dim angen amddiffyniad. Gallwch aros y tu allan yn ddiogel.",
+	"uv.advice.moderate":  "Mae angen amddiffyniad. Chwiliwch am gysgod yng nghanol y dydd, gorchuddiwch eich croen a gwisgwch eli haul a het.",
+	"uv.advice.high":      "Mae angen amddiffyniad. Chwiliwch am gysgod yng nghanol y dydd, gorchuddiwch eich croen a gwisgwch eli haul a het.",
+	"uv.advice.very-high": "Mae angen amddiffyniad ychwanegol. Osgowch fod y tu allan yng nghanol y dydd. Gwnewch yn siŵr eich bod yn chwilio am gysgod. Mae crys, eli haul a het yn hanfodol.",
+	"uv.advice.extreme":   "Mae angen amddiffyniad ychwanegol. Osgowch fod y tu allan yng nghanol y dydd. Gwnewch yn siŵr eich bod yn chwilio am gysgod. Mae crys, eli haul a het yn hanfodol.",
+
+	"compass.N":    "Gogledd",
+	"compass.NNE":  "Gogledd-gogledd-ddwyrain",
+	"compass.NE":   "Gogledd-ddwyrain",
+	"compass.ENE":  "Dwyrain-gogledd-ddwyrain",
+	"compass.E":    "Dwyrain",
+	"compass.ESE":  "Dwyrain-de-ddwyrain",
+	"compass.SE":   "De-ddwyrain",
+	"compass.SSE":  "De-de-ddwyrain",
+	"compass.S":    "De",
+	"compass.SSW":  "De-de-orllewin",
+	"compass.SW":   "De-orllewin",
+	"compass.WSW":  "Gorllewin-de-orllewin",
+	"compass.W":    "Gorllewin",
+	"compass.WNW":  "Gorllewin-gogledd-orllewin",
+	"compass.NW":   "Gogledd-orllewin",
+	"compass.NNW":  "Gogledd-gogledd-orllewin",
+	"compass.CALM": "Tawel",
+	"compass.VRB":  "Amrywiol",
+
+	"extreme.HMAXT": "Tymheredd uchaf uchaf",
+	"extreme.LMAXT": "Tymheredd uchaf isaf",
+	"extreme.HMINT": "Tymheredd isaf uchaf",
+	"extreme.LMINT": "Tymheredd isaf isaf",
+	"extreme.HRAIN": "Glawiad uchaf",
+	"extreme.HSUN":  "Heulwen uchaf",
+}
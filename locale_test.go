@@ -0,0 +1,27 @@
+package datapoint_test
+
+import (
+	"strings"
+	"testing"
+
+	dp "github.com/vitineth/datapoint"
+)
+
+func TestWeatherTypeDescriptions(t *testing.T) {
+	for w := dp.WeatherTypeNotAvailable; w <= dp.WeatherTypeThunder; w++ {
+		english := w.String()
+		if strings.HasPrefix(english, "Unknown") || english != w.Localise(dp.LanguageEnglish) {
+			t.Errorf("expected an English description of %v but got %q", w.Code(), english)
+		}
+		if welsh := w.Localise(dp.LanguageWelsh); welsh == english {
+			t.Errorf("expected a Welsh description of %v but got %q", w.Code(), welsh)
+		}
+	}
+
+	if w := dp.WeatherType(31); w.String() != "Unknown (31)" {
+		t.Errorf("expected an undefined weather type to be unknown but got %q", w.String())
+	}
+	if w := dp.WeatherTypeLightRain; w.String() != "Light rain" || w.Localise("cy-GB") != "Glaw ysgafn" {
+		t.Errorf("unexpected descriptions %q and %q", w.String(), w.Localise("cy-GB"))
+	}
+}
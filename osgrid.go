@@ -0,0 +1,319 @@
+package datapoint
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Position is anything which can be located on the WGS84 datum used by DataPoint, such as a LatLon, an OSGB36 grid
+// position or a Site
+type Position interface {
+	WGS84() LatLon
+}
+
+// WGS84 returns the position itself
+func (p LatLon) WGS84() LatLon {
+	return p
+}
+
+// WGS84 returns the position of the site
+func (s Site) WGS84() LatLon {
+	return s.LatLon()
+}
+
+// OSGB36 is a position on the Ordnance Survey National Grid, given as an easting and northing in metres from the false
+// origin of the grid
+type OSGB36 struct {
+	Easting  float64
+	Northing float64
+}
+
+type ellipsoid struct {
+	a float64
+	b float64
+}
+
+// helmert holds the translation in metres, scale in parts per million and rotation in arc seconds of a Helmert
+// transform
+type helmert struct {
+	tx, ty, tz float64
+	s          float64
+	rx, ry, rz float64
+}
+
+var (
+	ellipsoidWGS84 = ellipsoid{a: 6378137, b: 6356752.314245}
+	ellipsoidAiry  = ellipsoid{a: 6377563.396, b: 6356256.909}
+
+	// wgs84ToOsgb36 is the transform published by the Ordnance Survey, which is accurate to within around 5m
+	wgs84ToOsgb36 = helmert{tx: -446.448, ty: 125.157, tz: -542.060, s: 20.4894, rx: -0.1502, ry: -0.2470, rz: -0.8421}
+)
+
+// National Grid projection constants
+const (
+	gridScale          = 0.9996012717
+	gridOriginLatitude = 49.0
+	gridOriginLong     = -2.0
+	gridFalseEasting   = 400000.0
+	gridFalseNorthing  = -100000.0
+)
+
+// WGS84ToOSGB36 converts a latitude and longitude on the WGS84 datum into one on the OSGB36 datum
+func WGS84ToOSGB36(p LatLon) LatLon {
+	return transformDatum(p, ellipsoidWGS84, ellipsoidAiry, wgs84ToOsgb36)
+}
+
+// OSGB36ToWGS84 converts a latitude and longitude on the OSGB36 datum into one on the WGS84 datum
+func OSGB36ToWGS84(p LatLon) LatLon {
+	inverse := helmert{
+		tx: -wgs84ToOsgb36.tx, ty: -wgs84ToOsgb36.ty, tz: -wgs84ToOsgb36.tz,
+		s:  -wgs84ToOsgb36.s,
+		rx: -wgs84ToOsgb36.rx, ry: -wgs84ToOsgb36.ry, rz: -wgs84ToOsgb36.rz,
+	}
+	return transformDatum(p, ellipsoidAiry, ellipsoidWGS84, inverse)
+}
+
+// OSGB36 returns the National Grid position of a WGS84 latitude and longitude
+func (p LatLon) OSGB36() OSGB36 {
+	return projectNationalGrid(WGS84ToOSGB36(p))
+}
+
+// WGS84 returns the WGS84 latitude and longitude of the grid position
+func (g OSGB36) WGS84() LatLon {
+	return OSGB36ToWGS84(g.LatLon())
+}
+
+// LatLon returns the latitude and longitude of the grid position on the OSGB36 datum. Use WGS84 for a position which
+// can be compared with DataPoint sites
+func (g OSGB36) LatLon() LatLon {
+	return unprojectNationalGrid(g)
+}
+
+// transformDatum converts a latitude and longitude between datums by converting to cartesian coordinates, applying
+// the Helmert transform and converting back
+func transformDatum(p LatLon, from ellipsoid, to ellipsoid, t helmert) LatLon {
+	lat, lon := p.Latitude*math.Pi/180, p.Longitude*math.Pi/180
+
+	e2 := 1 - from.b*from.b/(from.a*from.a)
+	nu := from.a / math.Sqrt(1-e2*math.Sin(lat)*math.Sin(lat))
+	x := nu * math.Cos(lat) * math.Cos(lon)
+	y := nu * math.Cos(lat) * math.Sin(lon)
+	z := (1 - e2) * nu * math.Sin(lat)
+
+	s := 1 + t.s/1e6
+	rx, ry, rz := t.rx/3600*math.Pi/180, t.ry/3600*math.Pi/180, t.rz/3600*math.Pi/180
+	x2 := t.tx + x*s - y*rz + z*ry
+	y2 := t.ty + x*rz + y*s - z*rx
+	z2 := t.tz - x*ry + y*rx + z*s
+
+	e2 = 1 - to.b*to.b/(to.a*to.a)
+	r := math.Hypot(x2, y2)
+	lat = math.Atan2(z2, r*(1-e2))
+	for range 10 {
+		nu = to.a / math.Sqrt(1-e2*math.Sin(lat)*math.Sin(lat))
+		next := math.Atan2(z2+e2*nu*math.Sin(lat), r)
+		if math.Abs(next-lat) < 1e-12 {
+			lat = next
+			break
+		}
+		lat = next
+	}
+	lon = math.Atan2(y2, x2)
+	return LatLon{Latitude: lat * 180 / math.Pi, Longitude: lon * 180 / math.Pi}
+}
+
+// meridionalArc returns the distance along the central meridian of the National Grid from the true origin to the
+// latitude, scaled by the grid scale factor
+func meridionalArc(lat float64) float64 {
+	a, b := ellipsoidAiry.a, ellipsoidAiry.b
+	n := (a - b) / (a + b)
+	lat0 := gridOriginLatitude * math.Pi / 180
+	d, s := lat-lat0, lat+lat0
+	return b * gridScale * ((1+n+5.0/4*n*n+5.0/4*n*n*n)*d -
+		(3*n+3*n*n+21.0/8*n*n*n)*math.Sin(d)*math.Cos(s) +
+		(15.0/8*n*n+15.0/8*n*n*n)*math.Sin(2*d)*math.Cos(2*s) -
+		35.0/24*n*n*n*math.Sin(3*d)*math.Cos(3*s))
+}
+
+// projectNationalGrid converts an OSGB36 latitude and longitude onto the National Grid transverse Mercator projection
+func projectNationalGrid(p LatLon) OSGB36 {
+	a, b := ellipsoidAiry.a, ellipsoidAiry.b
+	e2 := 1 - b*b/(a*a)
+	lat, lon := p.Latitude*math.Pi/180, p.Longitude*math.Pi/180
+	lon0 := gridOriginLong * math.Pi / 180
+
+	sin, cos, tan := math.Sin(lat), math.Cos(lat), math.Tan(lat)
+	nu := a * gridScale / math.Sqrt(1-e2*sin*sin)
+	rho := a * gridScale * (1 - e2) / math.Pow(1-e2*sin*sin, 1.5)
+	eta2 := nu/rho - 1
+
+	i := meridionalArc(lat) + gridFalseNorthing
+	ii := nu / 2 * sin * cos
+	iii := nu / 24 * sin * cos * cos * cos * (5 - tan*tan + 9*eta2)
+	iiia := nu / 720 * sin * math.Pow(cos, 5) * (61 - 58*tan*tan + math.Pow(tan, 4))
+	iv := nu * cos
+	v := nu / 6 * cos * cos * cos * (nu/rho - tan*tan)
+	vi := nu / 120 * math.Pow(cos, 5) * (5 - 18*tan*tan + math.Pow(tan, 4) + 14*eta2 - 58*tan*tan*eta2)
+
+	dl := lon - lon0
+	return OSGB36{
+		Easting:  gridFalseEasting + iv*dl + v*math.Pow(dl, 3) + vi*math.Pow(dl, 5),
+		Northing: i + ii*dl*dl + iii*math.Pow(dl, 4) + iiia*math.Pow(dl, 6),
+	}
+}
+
+// unprojectNationalGrid converts a National Grid position into an OSGB36 latitude and longitude
+func unprojectNationalGrid(g OSGB36) LatLon {
+	a, b := ellipsoidAiry.a, ellipsoidAiry.b
+	e2 := 1 - b*b/(a*a)
+	lat := gridOriginLatitude * math.Pi / 180
+	lon0 := gridOriginLong * math.Pi / 180
+
+	m := 0.0
+	for range 100 {
+		lat = (g.Northing-gridFalseNorthing-m)/(a*gridScale) + lat
+		m = meridionalArc(lat)
+		if math.Abs(g.Northing-gridFalseNorthing-m) < 0.00001 {
+			break
+		}
+	}
+
+	sin, cos, tan := math.Sin(lat), math.Cos(lat), math.Tan(lat)
+	nu := a * gridScale / math.Sqrt(1-e2*sin*sin)
+	rho := a * gridScale * (1 - e2) / math.Pow(1-e2*sin*sin, 1.5)
+	eta2 := nu/rho - 1
+	sec := 1 / cos
+
+	vii := tan / (2 * rho * nu)
+	viii := tan / (24 * rho * math.Pow(nu, 3)) * (5 + 3*tan*tan + eta2 - 9*tan*tan*eta2)
+	ix := tan / (720 * rho * math.Pow(nu, 5)) * (61 + 90*tan*tan + 45*math.Pow(tan, 4))
+	x := sec / nu
+	xi := sec / (6 * math.Pow(nu, 3)) * (nu/rho + 2*tan*tan)
+	xii := sec / (120 * math.Pow(nu, 5)) * (5 + 28*tan*tan + 24*math.Pow(tan, 4))
+	xiia := sec / (5040 * math.Pow(nu, 7)) * (61 + 662*tan*tan + 1320*math.Pow(tan, 4) + 720*math.Pow(tan, 6))
+
+	de := g.Easting - gridFalseEasting
+	lat = lat - vii*de*de + viii*math.Pow(de, 4) - ix*math.Pow(de, 6)
+	lon := lon0 + x*de - xi*math.Pow(de, 3) + xii*math.Pow(de, 5) - xiia*math.Pow(de, 7)
+	return LatLon{Latitude: lat * 180 / math.Pi, Longitude: lon * 180 / math.Pi}
+}
+
+// ParseGridReference converts an Ordnance Survey grid reference such as 'SX 919 925' or 'SX9192' into the position of
+// the south west corner of the square it describes. Spaces are optional and any even number of digits up to ten is
+// accepted
+func ParseGridReference(value string) (OSGB36, error) {
+	ref := strings.ToUpper(strings.Join(strings.Fields(value), ""))
+	if len(ref) < 2 || !unicode.IsLetter(rune(ref[0])) || !unicode.IsLetter(rune(ref[1])) {
+		return OSGB36{}, fmt.Errorf("grid reference %v must start with two letters", value)
+	}
+
+	l1, l2 := gridLetterIndex(ref[0]), gridLetterIndex(ref[1])
+	if l1 < 0 || l2 < 0 {
+		return OSGB36{}, fmt.Errorf("grid reference %v has invalid square letters", value)
+	}
+	e100k := ((l1-2+25)%5)*5 + l2%5
+	n100k := (19 - (l1/5)*5) - l2/5
+	if e100k < 0 || e100k > 6 || n100k < 0 || n100k > 12 {
+		return OSGB36{}, fmt.Errorf("grid reference %v is outside of the National Grid", value)
+	}
+
+	digits := ref[2:]
+	if len(digits)%2 != 0 || len(digits) > 10 {
+		return OSGB36{}, fmt.Errorf("grid reference %v must have an even number of digits up to ten", value)
+	}
+	half := len(digits) / 2
+	scale := math.Pow10(5 - half)
+
+	var e, n float64
+	if half > 0 {
+		ed, err := strconv.ParseUint(digits[:half], 10, 32)
+		if err != nil {
+			return OSGB36{}, fmt.Errorf("failed to parse grid reference %v easting: %w", value, err)
+		}
+		nd, err := strconv.ParseUint(digits[half:], 10, 32)
+		if err != nil {
+			return OSGB36{}, fmt.Errorf("failed to parse grid reference %v northing: %w", value, err)
+		}
+		e, n = float64(ed)*scale, float64(nd)*scale
+	}
+
+	return OSGB36{Easting: float64(e100k)*100000 + e, Northing: float64(n100k)*100000 + n}, nil
+}
+
+// gridLetterIndex returns the position of the letter in the 25 letter National Grid alphabet, which omits I, or -1
+func gridLetterIndex(letter byte) int {
+	if letter < 'A' || letter > 'Z' || letter == 'I' {
+		return -1
+	}
+	i := int(letter - 'A')
+	if i > 7 {
+		i--
+	}
+	return i
+}
+
+// GridReference formats the position as an Ordnance Survey grid reference with the number of digits, e.g. 6 gives
+// 'SX 919 925'. digits must be even and between 0 and 10. Positions outside of the National Grid return an error
+func (g OSGB36) GridReference(digits int) (string, error) {
+	if digits < 0 || digits > 10 || digits%2 != 0 {
+		return "", fmt.Errorf("grid reference digits must be even and between 0 and 10, got %v", digits)
+	}
+	e100k, n100k := int(math.Floor(g.Easting/100000)), int(math.Floor(g.Northing/100000))
+	if e100k < 0 || e100k > 6 || n100k < 0 || n100k > 12 {
+		return "", fmt.Errorf("position %v,%v is outside of the National Grid", g.Easting, g.Northing)
+	}
+
+	l1 := (19 - n100k) - (19-n100k)%5 + (e100k+10)/5
+	l2 := (19-n100k)*5%25 + e100k%5
+	if l1 > 7 {
+		l1++
+	}
+	if l2 > 7 {
+		l2++
+	}
+	letters := string(rune('A'+l1)) + string(rune('A'+l2))
+	if digits == 0 {
+		return letters, nil
+	}
+
+	half := digits / 2
+	scale := math.Pow10(5 - half)
+	e := int(math.Floor(math.Mod(g.Easting, 100000) / scale))
+	n := int(math.Floor(math.Mod(g.Northing, 100000) / scale))
+	return fmt.Sprintf("%v %0*d %0*d", letters, half, e, half, n), nil
+}
+
+// ParsePosition converts a location given as an Ordnance Survey grid reference ('SX 919 925'), an OSGB36 easting and
+// northing separated by a comma ('291900,92500') or a WGS84 latitude and longitude separated by a comma
+// ('50.7236,-3.5275') into a Position. A pair of numbers is treated as an easting and northing if both are larger
+// than the range of valid latitudes and longitudes
+func ParsePosition(value string) (Position, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed != "" && unicode.IsLetter(rune(trimmed[0])) {
+		return ParseGridReference(trimmed)
+	}
+
+	first, second, ok := strings.Cut(trimmed, ",")
+	if !ok {
+		return nil, fmt.Errorf("position %v must be a grid reference or two numbers separated by a comma", value)
+	}
+	a, err := strconv.ParseFloat(strings.TrimSpace(first), 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse position %v: %w", value, err)
+	}
+	b, err := strconv.ParseFloat(strings.TrimSpace(second), 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse position %v: %w", value, err)
+	}
+
+	if math.Abs(a) > 180 && math.Abs(b) > 180 {
+		return OSGB36{Easting: a, Northing: b}, nil
+	}
+	if math.Abs(a) > 90 || math.Abs(b) > 180 {
+		return nil, fmt.Errorf("position %v is not a valid latitude and longitude", value)
+	}
+	return LatLon{Latitude: a, Longitude: b}, nil
+}
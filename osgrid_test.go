@@ -0,0 +1,128 @@
+package datapoint_test
+
+import (
+	"math"
+	"testing"
+
+	dp "github.com/vitineth/datapoint"
+)
+
+func degrees(d float64, m float64, s float64) float64 {
+	return d + m/60 + s/3600
+}
+
+// arcSeconds returns the distance between two positions in seconds of arc along each axis
+func arcSeconds(a dp.LatLon, b dp.LatLon) (float64, float64) {
+	return math.Abs(a.Latitude-b.Latitude) * 3600, math.Abs(a.Longitude-b.Longitude) * 3600
+}
+
+// The worked example from the Ordnance Survey guide to coordinate systems in Great Britain, a point near Caister
+var (
+	caisterGrid   = dp.OSGB36{Easting: 651409.903, Northing: 313177.270}
+	caisterOsgb36 = dp.LatLon{Latitude: degrees(52, 39, 27.2531), Longitude: degrees(1, 43, 4.5177)}
+	caisterWgs84  = dp.LatLon{Latitude: degrees(52, 39, 28.72), Longitude: degrees(1, 42, 57.79)}
+)
+
+func TestNationalGridProjection(t *testing.T) {
+	if lat, lon := arcSeconds(caisterGrid.LatLon(), caisterOsgb36); lat > 1e-4 || lon > 1e-4 {
+		t.Errorf("expected %v to be at %+v but got %+v", caisterGrid, caisterOsgb36, caisterGrid.LatLon())
+	}
+
+	grid := dp.OSGB36ToWGS84(caisterOsgb36).OSGB36()
+	if math.Abs(grid.Easting-caisterGrid.Easting) > 0.01 || math.Abs(grid.Northing-caisterGrid.Northing) > 0.01 {
+		t.Errorf("expected %+v to be at %+v but got %+v", caisterOsgb36, caisterGrid, grid)
+	}
+}
+
+func TestHelmertTransform(t *testing.T) {
+	wgs84 := dp.OSGB36ToWGS84(caisterOsgb36)
+	if lat, lon := arcSeconds(wgs84, caisterWgs84); lat > 0.01 || lon > 0.01 {
+		t.Errorf("expected %+v on OSGB36 to be %+v on WGS84 but got %+v", caisterOsgb36, caisterWgs84, wgs84)
+	}
+
+	osgb36 := dp.WGS84ToOSGB36(wgs84)
+	if lat, lon := arcSeconds(osgb36, caisterOsgb36); lat > 1e-3 || lon > 1e-3 {
+		t.Errorf("expected %+v to round trip to %+v but got %+v", wgs84, caisterOsgb36, osgb36)
+	}
+
+	grid := caisterGrid.WGS84().OSGB36()
+	if math.Abs(grid.Easting-caisterGrid.Easting) > 0.05 || math.Abs(grid.Northing-caisterGrid.Northing) > 0.05 {
+		t.Errorf("expected %+v to round trip through WGS84 but got %+v", caisterGrid, grid)
+	}
+}
+
+func TestGridReference(t *testing.T) {
+	tests := []struct {
+		reference string
+		digits    int
+		expected  dp.OSGB36
+		formatted string
+	}{
+		{"TG 51409 13177", 10, dp.OSGB36{Easting: 651409, Northing: 313177}, "TG 51409 13177"},
+		{"SX 919 925", 6, dp.OSGB36{Easting: 291900, Northing: 92500}, "SX 919 925"},
+		{"sx919925", 6, dp.OSGB36{Easting: 291900, Northing: 92500}, "SX 919 925"},
+		{"SX 9192", 4, dp.OSGB36{Easting: 291000, Northing: 92000}, "SX 91 92"},
+		{"SX", 0, dp.OSGB36{Easting: 200000, Northing: 0}, "SX"},
+		{"NN 166 712", 6, dp.OSGB36{Easting: 216600, Northing: 771200}, "NN 166 712"},
+		{"HP 6 1", 2, dp.OSGB36{Easting: 460000, Northing: 1210000}, "HP 6 1"},
+	}
+	for _, test := range tests {
+		grid, err := dp.ParseGridReference(test.reference)
+		if err != nil {
+			t.Errorf("failed to parse %q: %v", test.reference, err)
+			continue
+		}
+		if grid != test.expected {
+			t.Errorf("expected %q to be at %+v but got %+v", test.reference, test.expected, grid)
+		}
+		formatted, err := grid.GridReference(test.digits)
+		if err != nil || formatted != test.formatted {
+			t.Errorf("expected %+v to format as %q but got %q, %v", grid, test.formatted, formatted, err)
+		}
+	}
+
+	// an SX reference survives a round trip through WGS84 at every precision. Half a metre is added so that the few
+	// millimetres lost in the round trip cannot move the position into the previous square
+	exeter, _ := dp.ParseGridReference("SX 91925 92571")
+	position := exeter.WGS84().OSGB36()
+	for digits := 0; digits <= 10; digits += 2 {
+		expected, _ := exeter.GridReference(digits)
+		actual, err := dp.OSGB36{Easting: position.Easting + 0.5, Northing: position.Northing + 0.5}.GridReference(digits)
+		if err != nil || actual != expected {
+			t.Errorf("expected %v digits to round trip as %q but got %q, %v", digits, expected, actual, err)
+		}
+	}
+}
+
+func TestInvalidGridReference(t *testing.T) {
+	for _, reference := range []string{
+		"",
+		"S",
+		"91 925",
+		"S1 919 925",
+		"IX 919 925",
+		"SI 919 925",
+		"XX 919 925",
+		"AA 919 925",
+		"SX 919 92",
+		"SX 9192 925",
+		"SX 1",
+		"SX 123456 123456",
+		"SX 9a9 925",
+	} {
+		if grid, err := dp.ParseGridReference(reference); err == nil {
+			t.Errorf("expected %q to be invalid but got %+v", reference, grid)
+		}
+	}
+
+	for _, digits := range []int{-2, 3, 12} {
+		if ref, err := caisterGrid.GridReference(digits); err == nil {
+			t.Errorf("expected %v digits to be invalid but got %q", digits, ref)
+		}
+	}
+	for _, grid := range []dp.OSGB36{{Easting: -1, Northing: 0}, {Easting: 700000, Northing: 0}, {Easting: 0, Northing: 1300000}} {
+		if ref, err := grid.GridReference(6); err == nil {
+			t.Errorf("expected %+v to be outside of the National Grid but got %q", grid, ref)
+		}
+	}
+}
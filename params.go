@@ -0,0 +1,238 @@
+package datapoint
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// ParameterType describes how the value of a forecast parameter is represented
+type ParameterType int
+
+const (
+	// ParameterTypeString values are kept as they are returned and appear in Forecast.StringParams
+	ParameterTypeString ParameterType = iota
+	// ParameterTypeInt values are parsed as integers and appear in Forecast.IntParams
+	ParameterTypeInt
+	// ParameterTypeFloat values are parsed as decimals and appear in Forecast.FloatParams
+	ParameterTypeFloat
+	// ParameterTypeEnum values must be one of ParameterDefinition.Values and appear in Forecast.StringParams
+	ParameterTypeEnum
+)
+
+// ParameterRange is the inclusive range of values which are valid for a numeric parameter
+type ParameterRange struct {
+	Min float64
+	Max float64
+}
+
+// Contains returns if the value lies within the range
+func (r ParameterRange) Contains(value float64) bool {
+	return value >= r.Min && value <= r.Max
+}
+
+// ParameterDefinition describes how the client should interpret the values of a single forecast parameter
+type ParameterDefinition struct {
+	// Name is the name of the parameter as it appears in the response, e.g. 'T'
+	Name string
+	// Type is the type of the parsed value, which controls which map of the Forecast it will appear in
+	Type ParameterType
+	// Units is the unit the parameter is expected to be returned in. If the units in the response differ a warning is
+	// raised
+	Units string
+	// Range is the valid range of values for numeric parameters, or nil if the value is unbounded
+	Range *ParameterRange
+	// Values is the set of valid values for enum parameters
+	Values []string
+	// Categorical marks a numeric parameter whose values are codes rather than measurements, such as the weather type,
+	// so that summaries count each value instead of averaging them. String and enum parameters are always categorical
+	Categorical bool
+	// Parse converts the raw value into the value stored on the forecast. It must return an int for ParameterTypeInt,
+	// a float64 for ParameterTypeFloat and a string otherwise. If nil the default parser for the type is used. A value
+	// which cannot be parsed fails the decoding of the whole forecast
+	Parse func(raw string) (any, error)
+}
+
+// categorical returns if the values of the parameter are summarised by counting rather than averaging
+func (p ParameterDefinition) categorical() bool {
+	return p.Categorical || p.Type == ParameterTypeString || p.Type == ParameterTypeEnum
+}
+
+// holds returns if the value is of the type stored for parameters of this type
+func (t ParameterType) holds(value any) bool {
+	switch value.(type) {
+	case int:
+		return t == ParameterTypeInt
+	case float64:
+		return t == ParameterTypeFloat
+	case string:
+		return t == ParameterTypeString || t == ParameterTypeEnum
+	default:
+		return false
+	}
+}
+
+func (p ParameterDefinition) parse(raw string) (any, error) {
+	if p.Parse != nil {
+		value, err := p.Parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		if !p.Type.holds(value) {
+			return nil, fmt.Errorf("parser returned unsupported type %T", value)
+		}
+		return value, nil
+	}
+
+	switch p.Type {
+	case ParameterTypeInt:
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return int(v), nil
+	case ParameterTypeFloat:
+		return strconv.ParseFloat(raw, 64)
+	default:
+		return raw, nil
+	}
+}
+
+// validate checks the parsed value against the range and values of the definition
+func (p ParameterDefinition) validate(value any) error {
+	switch v := value.(type) {
+	case int:
+		if p.Range != nil && !p.Range.Contains(float64(v)) {
+			return fmt.Errorf("value %v is outside of the range %v to %v", v, p.Range.Min, p.Range.Max)
+		}
+	case float64:
+		if p.Range != nil && !p.Range.Contains(v) {
+			return fmt.Errorf("value %v is outside of the range %v to %v", v, p.Range.Min, p.Range.Max)
+		}
+	case string:
+		if p.Type == ParameterTypeEnum && !slices.Contains(p.Values, v) {
+			return fmt.Errorf("value %v is not one of %v", v, p.Values)
+		}
+	}
+	return nil
+}
+
+// ParameterRegistry holds the set of parameters the client knows how to interpret. Parameters which are not
+// registered are kept as strings
+type ParameterRegistry struct {
+	mu          sync.RWMutex
+	definitions map[string]ParameterDefinition
+}
+
+var (
+	percentRange     = &ParameterRange{Min: 0, Max: 100}
+	temperatureRange = &ParameterRange{Min: -100, Max: 100}
+	speedRange       = &ParameterRange{Min: 0, Max: 300}
+
+	compassValues = []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+
+	defaultParameters = []ParameterDefinition{
+		{Name: string(KnownParameterFeelsLikeTemp), Type: ParameterTypeInt, Units: "C", Range: temperatureRange},
+		{Name: string(KnownParameterWindGust), Type: ParameterTypeInt, Units: "mph", Range: speedRange},
+		{Name: string(KnownParameterWindGustNoon), Type: ParameterTypeInt, Units: "mph", Range: speedRange},
+		{Name: string(KnownParameterWindGustMidnight), Type: ParameterTypeInt, Units: "mph", Range: speedRange},
+		{Name: string(KnownParameterScreenRelativeHumidity), Type: ParameterTypeInt, Units: "%", Range: percentRange},
+		{Name: string(KnownParameterScreenRelativeHumidityNoon), Type: ParameterTypeInt, Units: "%", Range: percentRange},
+		{Name: string(KnownParameterScreenRelativeHumidityMidnight), Type: ParameterTypeInt, Units: "%", Range: percentRange},
+		{Name: string(KnownParameterTemperature), Type: ParameterTypeInt, Units: "C", Range: temperatureRange},
+		{Name: string(KnownParameterDayMaximumTemperature), Type: ParameterTypeInt, Units: "C", Range: temperatureRange},
+		{Name: string(KnownParameterNightMinimumTemperature), Type: ParameterTypeInt, Units: "C", Range: temperatureRange},
+		{Name: string(KnownParameterFeelsLikeDayMaximumTemperature), Type: ParameterTypeInt, Units: "C", Range: temperatureRange},
+		{Name: string(KnownParameterFeelsLikeNightMinimumTemperature), Type: ParameterTypeInt, Units: "C", Range: temperatureRange},
+		{Name: string(KnownParameterVisibility), Type: ParameterTypeEnum, Units: "", Values: []string{
+			string(VisibilityUnknown), string(VisibilityVeryPoor), string(VisibilityPoor), string(VisibilityModerate),
+			string(VisibilityGood), string(VisibilityVeryGood), string(VisibilityExcellent),
+		}},
+		{Name: string(KnownParameterWindDirection), Type: ParameterTypeEnum, Units: "compass", Values: compassValues},
+		{Name: string(KnownParameterWindSpeed), Type: ParameterTypeInt, Units: "mph", Range: speedRange},
+		{Name: string(KnownParameterMaxUvIndex), Type: ParameterTypeInt, Units: "", Range: &ParameterRange{Min: 0, Max: 20}},
+		{Name: string(KnownParameterWeatherType), Type: ParameterTypeInt, Units: "", Categorical: true, Parse: parseWeatherTypeParameter},
+		{Name: string(KnownParameterPrecipitationProbability), Type: ParameterTypeInt, Units: "%", Range: percentRange},
+		{Name: string(KnownParameterPrecipitationProbabilityDay), Type: ParameterTypeInt, Units: "%", Range: percentRange},
+		{Name: string(KnownParameterPrecipitationProbabilityNight), Type: ParameterTypeInt, Units: "%", Range: percentRange},
+		{Name: string(KnownParameterDewPoint), Type: ParameterTypeFloat, Units: "C", Range: temperatureRange},
+	}
+)
+
+// NewParameterRegistry returns a registry containing definitions for every KnownParameter
+func NewParameterRegistry() *ParameterRegistry {
+	r := &ParameterRegistry{definitions: map[string]ParameterDefinition{}}
+	for _, p := range defaultParameters {
+		r.definitions[p.Name] = p
+	}
+	return r
+}
+
+// Register adds a parameter definition to the registry, replacing any existing definition with the same name
+func (r *ParameterRegistry) Register(definition ParameterDefinition) error {
+	if definition.Name == "" || definition.Name == "$" {
+		return errors.New("parameter definition must have a valid name")
+	}
+	if definition.Type == ParameterTypeEnum && len(definition.Values) == 0 {
+		return fmt.Errorf("enum parameter %v must declare its values", definition.Name)
+	}
+	if definition.Range != nil && definition.Range.Min > definition.Range.Max {
+		return fmt.Errorf("parameter %v has an empty range", definition.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.definitions[definition.Name] = definition
+	return nil
+}
+
+// Lookup returns the definition of the named parameter if one has been registered
+func (r *ParameterRegistry) Lookup(name string) (ParameterDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	definition, ok := r.definitions[name]
+	return definition, ok
+}
+
+type parameterRegistry struct {
+	registry *ParameterRegistry
+}
+
+func (p parameterRegistry) apply(client *DataPointClient) {
+	client.parameters = p.registry
+}
+
+// WithParameterRegistry sets the registry used to interpret forecast parameters. This can be used to share a registry
+// containing additional parameters between clients. The registry must not be nil
+func WithParameterRegistry(registry *ParameterRegistry) Opt {
+	return parameterRegistry{registry: registry}
+}
+
+// Parameters returns the registry used by this client to interpret forecast parameters. Additional parameters can be
+// registered on it at any time
+func (d *DataPointClient) Parameters() *ParameterRegistry {
+	return d.parameters
+}
+
+// standardUnit returns the units the named parameter is defined with by default, if it has any
+func standardUnit(name string) (Unit, bool) {
+	for _, p := range defaultParameters {
+		if p.Name != name {
+			continue
+		}
+		u, err := ParseUnit(p.Units)
+		return u, err == nil && u != UnitNone
+	}
+	return UnitNone, false
+}
+
+// parseWeatherTypeParameter allows the 'NA' weather type to be stored as an int
+func parseWeatherTypeParameter(raw string) (any, error) {
+	w, err := ParseWeatherType(raw)
+	if err != nil {
+		return nil, err
+	}
+	return int(w), nil
+}
@@ -0,0 +1,139 @@
+package datapoint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	dp "github.com/vitineth/datapoint"
+	"github.com/vitineth/datapoint/datapointtest"
+)
+
+var (
+	record = flag.Bool("record", false, "record the fixtures from the datapointtest emulator")
+	update = flag.Bool("update", false, "rewrite the golden files from the decoded fixtures")
+)
+
+const (
+	fixtureDir = "testdata/emulator"
+	goldenDir  = "testdata/golden"
+)
+
+// replayClient returns a client which serves every request from the fixtures, or records them from the emulator with
+// the single-object quirk enabled if the -record flag is set. The fixtures are emulator output rather than responses
+// from the live service, so they pin the decoding of a fixed set of bytes but cannot detect changes to the real schema
+func replayClient(t *testing.T) *dp.DataPointClient {
+	t.Helper()
+	recorder := datapointtest.NewRecorder(fixtureDir, datapointtest.ModeReplay, nil)
+	opt := []dp.Opt{dp.WithApiKey("replay"), dp.WithDecodingMode(dp.DecodingModeStrict)}
+
+	if *record {
+		server := datapointtest.NewServer()
+		t.Cleanup(server.Close)
+		server.SetSingleObjectQuirk(true)
+		recorder = datapointtest.NewRecorder(fixtureDir, datapointtest.ModeRecord, server.Server.Client().Transport)
+		opt = append(opt, dp.WithApiKey(datapointtest.DefaultApiKey), dp.WithBaseURI(server.BaseURI()))
+	}
+
+	client, err := dp.NewClient(append(opt, dp.WithHttpClient(recorder.Client()))...)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+// checkGolden compares the JSON encoding of value against the golden file with the name, rewriting it if the -update
+// or -record flag is set
+func checkGolden(t *testing.T, name string, value any) {
+	t.Helper()
+	actual, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to encode %v: %v", name, err)
+	}
+	actual = append(actual, '\n')
+
+	path := filepath.Join(goldenDir, name+".json")
+	if *update || *record {
+		if err := os.MkdirAll(goldenDir, 0o755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatalf("failed to write golden file %v: %v", path, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file %v: %v", path, err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("decoded %v does not match %v, run go test -update if the change is expected\n%s", name, path, actual)
+	}
+}
+
+func TestReplayForecastSiteList(t *testing.T) {
+	sites, err := replayClient(t).ForecastSiteList()
+	if err != nil {
+		t.Fatalf("failed to decode site list: %v", err)
+	}
+	checkGolden(t, "forecast-sites", sites)
+}
+
+func TestReplayThreeHourlyForecast(t *testing.T) {
+	rep, err := replayClient(t).FiveDayForecast(dp.ResolutionThreeHourly, 310069, nil)
+	if err != nil {
+		t.Fatalf("failed to decode forecast: %v", err)
+	}
+	checkGolden(t, "forecast-3hourly", rep)
+}
+
+func TestReplayDailyForecast(t *testing.T) {
+	rep, err := replayClient(t).FiveDayForecast(dp.ResolutionDaily, 310069, nil)
+	if err != nil {
+		t.Fatalf("failed to decode forecast: %v", err)
+	}
+	for _, period := range rep.Location.Period {
+		for _, f := range period.Forecasts {
+			if f.Segment == dp.SegmentNone {
+				t.Errorf("expected the daily forecast at %v to have a segment", f.Time)
+			}
+		}
+	}
+	checkGolden(t, "forecast-daily", rep)
+}
+
+func TestReplaySingleTimeStepForecast(t *testing.T) {
+	// a single time step is returned with the Period and Rep arrays collapsed into bare objects
+	at := time.Date(2024, time.March, 14, 18, 0, 0, 0, time.UTC)
+	rep, err := replayClient(t).FiveDayForecast(dp.ResolutionThreeHourly, 310069, &at)
+	if err != nil {
+		t.Fatalf("failed to decode forecast: %v", err)
+	}
+	if len(rep.Location.Period) != 1 || len(rep.Location.Period[0].Forecasts) != 1 {
+		t.Fatalf("expected a single forecast but got %+v", rep.Location.Period)
+	}
+	if f := rep.Location.Period[0].Forecasts[0]; !f.Time.Equal(at) {
+		t.Errorf("expected the forecast to be for %v but got %v", at, f.Time)
+	}
+	checkGolden(t, "forecast-3hourly-single", rep)
+}
+
+func TestReplayLatestExtremes(t *testing.T) {
+	extremes, err := replayClient(t).UkExtremesLatest()
+	if err != nil {
+		t.Fatalf("failed to decode extremes: %v", err)
+	}
+	for _, region := range extremes.Regions {
+		for _, extreme := range region.Extremes {
+			if !extreme.Type.IsKnown() {
+				t.Errorf("unknown extreme type %q in region %v", extreme.Type, region.Id)
+			}
+		}
+	}
+	checkGolden(t, "extremes-latest", extremes)
+}
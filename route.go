@@ -0,0 +1,308 @@
+package datapoint
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// RouteOptions describes when a journey along a route takes place
+type RouteOptions struct {
+	// Departure is the time the journey leaves the first point. It is ignored if ETAs is set
+	Departure time.Time
+	// Speed is the average speed of the journey in metres per second, used to estimate when each point is reached. It
+	// is ignored if ETAs is set
+	Speed float64
+	// ETAs is the estimated time of arrival at each point of the route, which must have one entry per point
+	ETAs []time.Time
+	// MaxSegmentLength is the length in metres above which a segment of the route is split into equal parts, so that
+	// long straight sections use more than one site. 0 means segments are never split
+	MaxSegmentLength float64
+}
+
+// HazardKind is a kind of weather which is hazardous to travel
+type HazardKind int
+
+// Hazard kinds are ordered from the most to the least disruptive, which is used to order hazards of the same level
+const (
+	HazardThunder HazardKind = iota
+	HazardSnow
+	HazardIce
+	HazardHighWind
+	HazardFog
+	HazardHeavyRain
+	HazardHail
+)
+
+var hazardKindNames = map[HazardKind]string{
+	HazardThunder:   "Thunder",
+	HazardSnow:      "Snow",
+	HazardIce:       "Ice",
+	HazardHighWind:  "High wind",
+	HazardFog:       "Fog",
+	HazardHeavyRain: "Heavy rain",
+	HazardHail:      "Hail",
+}
+
+// String returns the English name of the hazard kind
+func (k HazardKind) String() string {
+	return hazardKindNames[k]
+}
+
+// HazardLevel is how disruptive a hazard is expected to be
+type HazardLevel int
+
+const (
+	HazardLevelMinor HazardLevel = iota + 1
+	HazardLevelModerate
+	HazardLevelSevere
+)
+
+// Hazard is a hazardous condition found in a forecast
+type Hazard struct {
+	Kind  HazardKind
+	Level HazardLevel
+	// Value is the measure which triggered the hazard, used to compare hazards of the same kind where a larger value
+	// is worse. This is the gust in mph for HazardHighWind, the degrees below 0°C for HazardIce and the weather type
+	// severity otherwise
+	Value float64
+}
+
+// RouteSegment is a part of a route along with the forecast for it
+type RouteSegment struct {
+	From LatLon
+	To   LatLon
+	// Distance is the great-circle length of the segment in metres
+	Distance float64
+	// Departure and Arrival are the estimated times the journey reaches the start and end of the segment
+	Departure time.Time
+	Arrival   time.Time
+	// Site is the forecast site nearest to the midpoint of the segment
+	Site SiteDistance
+	// Forecast is the three hourly forecast of the site valid when the journey reaches the midpoint of the segment.
+	// HasForecast is false if no forecast was available at that time, or the service had no forecast for the site
+	Forecast    Forecast
+	HasForecast bool
+	// Hazards are the hazards in the forecast, most severe first
+	Hazards []Hazard
+}
+
+// RouteHazard is the worst occurrence of a kind of hazard along a route
+type RouteHazard struct {
+	Hazard
+	// Segment is the index of the segment of the route the hazard occurs on
+	Segment int
+}
+
+// RouteWeather is the weather along a route
+type RouteWeather struct {
+	Segments []RouteSegment
+	// WorstHazards holds the worst occurrence of each kind of hazard along the route, most severe first
+	WorstHazards []RouteHazard
+}
+
+// ForecastRoute finds the weather along a route given as a polyline of at least two points. Each segment uses the
+// nearest site in the index to its midpoint, and the three hourly forecast of that site which is valid when the
+// journey is expected to reach the midpoint. Forecasts are fetched with forecast, which is called once per site and may
+// return nil if there is no forecast for the site
+func ForecastRoute(points []Position, options RouteOptions, index *SpatialIndex, forecast func(siteId int) (*SiteRep, error)) (*RouteWeather, error) {
+	if index == nil {
+		return nil, errors.New("no spatial index provided to find sites along the route")
+	}
+	segments, err := planRoute(points, options)
+	if err != nil {
+		return nil, err
+	}
+
+	reps := map[int]*SiteRep{}
+	result := &RouteWeather{Segments: segments}
+	for i := range result.Segments {
+		segment := &result.Segments[i]
+		midpoint := intermediatePoint(segment.From, segment.To, 0.5)
+		site, ok := index.Nearest(midpoint)
+		if !ok {
+			return nil, errors.New("no sites available to forecast the route")
+		}
+		segment.Site = site
+
+		rep, ok := reps[site.Site.Id]
+		if !ok {
+			rep, err = forecast(site.Site.Id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch forecast for site %v: %w", site.Site.Id, err)
+			}
+			reps[site.Site.Id] = rep
+		}
+		if rep == nil {
+			continue
+		}
+
+		at := segment.Departure.Add(segment.Arrival.Sub(segment.Departure) / 2)
+		segment.Forecast, segment.HasForecast = rep.ForecastAt(at)
+		if segment.HasForecast {
+			segment.Hazards = segment.Forecast.Hazards()
+		}
+	}
+
+	worst := map[HazardKind]RouteHazard{}
+	for i, segment := range result.Segments {
+		for _, h := range segment.Hazards {
+			existing, ok := worst[h.Kind]
+			if !ok || h.Level > existing.Level || (h.Level == existing.Level && h.Value > existing.Value) {
+				worst[h.Kind] = RouteHazard{Hazard: h, Segment: i}
+			}
+		}
+	}
+	for _, h := range worst {
+		result.WorstHazards = append(result.WorstHazards, h)
+	}
+	sort.Slice(result.WorstHazards, func(i, j int) bool {
+		a, b := result.WorstHazards[i], result.WorstHazards[j]
+		if a.Level != b.Level {
+			return a.Level > b.Level
+		}
+		return a.Kind < b.Kind
+	})
+	return result, nil
+}
+
+// RouteForecast finds the weather along a route as described by ForecastRoute, fetching the three hourly forecast of
+// each site used
+func (d *DataPointClient) RouteForecast(points []Position, options RouteOptions, index *SpatialIndex) (*RouteWeather, error) {
+	return ForecastRoute(points, options, index, func(siteId int) (*SiteRep, error) {
+		return d.FiveDayForecast(ResolutionThreeHourly, siteId, nil)
+	})
+}
+
+// planRoute splits the route into segments and estimates when each is reached
+func planRoute(points []Position, options RouteOptions) ([]RouteSegment, error) {
+	if len(points) < 2 {
+		return nil, errors.New("a route must have at least two points")
+	}
+	if options.ETAs != nil && len(options.ETAs) != len(points) {
+		return nil, fmt.Errorf("route has %v points but %v ETAs", len(points), len(options.ETAs))
+	}
+	if options.ETAs == nil && options.Speed <= 0 {
+		return nil, errors.New("a route needs either a positive speed or an ETA for each point")
+	}
+
+	var segments []RouteSegment
+	eta := options.Departure
+	if options.ETAs != nil {
+		eta = options.ETAs[0]
+	}
+
+	for i := 1; i < len(points); i++ {
+		from, to := points[i-1].WGS84(), points[i].WGS84()
+		distance := Distance(from, to)
+
+		var arrival time.Time
+		if options.ETAs != nil {
+			arrival = options.ETAs[i]
+			if arrival.Before(eta) {
+				return nil, fmt.Errorf("ETA of point %v is before the ETA of the previous point", i)
+			}
+		} else {
+			arrival = eta.Add(time.Duration(distance / options.Speed * float64(time.Second)))
+		}
+
+		parts := 1
+		if options.MaxSegmentLength > 0 && distance > options.MaxSegmentLength {
+			parts = int(math.Ceil(distance / options.MaxSegmentLength))
+		}
+		for p := 0; p < parts; p++ {
+			start, end := float64(p)/float64(parts), float64(p+1)/float64(parts)
+			segments = append(segments, RouteSegment{
+				From:      intermediatePoint(from, to, start),
+				To:        intermediatePoint(from, to, end),
+				Distance:  distance / float64(parts),
+				Departure: eta.Add(time.Duration(float64(arrival.Sub(eta)) * start)),
+				Arrival:   eta.Add(time.Duration(float64(arrival.Sub(eta)) * end)),
+			})
+		}
+		eta = arrival
+	}
+	return segments, nil
+}
+
+// intermediatePoint returns the point the fraction of the way along the great circle from a to b
+func intermediatePoint(a LatLon, b LatLon, fraction float64) LatLon {
+	if fraction <= 0 {
+		return a
+	}
+	if fraction >= 1 {
+		return b
+	}
+	va, vb := unitVector(a), unitVector(b)
+	angle := math.Acos(math.Max(-1, math.Min(1, va[0]*vb[0]+va[1]*vb[1]+va[2]*vb[2])))
+	if angle < 1e-12 {
+		return a
+	}
+	wa, wb := math.Sin((1-fraction)*angle)/math.Sin(angle), math.Sin(fraction*angle)/math.Sin(angle)
+	x, y, z := wa*va[0]+wb*vb[0], wa*va[1]+wb*vb[1], wa*va[2]+wb*vb[2]
+	return LatLon{
+		Latitude:  math.Atan2(z, math.Hypot(x, y)) * 180 / math.Pi,
+		Longitude: math.Atan2(y, x) * 180 / math.Pi,
+	}
+}
+
+// Hazards returns the conditions in the forecast which are hazardous to travel, most severe first
+func (f Forecast) Hazards() []Hazard {
+	var hazards []Hazard
+	w, hasWeather := f.WeatherType()
+	if hasWeather {
+		severity := float64(w.Severity())
+		switch {
+		case w.IsThunder():
+			hazards = append(hazards, Hazard{Kind: HazardThunder, Level: HazardLevelSevere, Value: severity})
+		case w == WeatherTypeHeavySnow || w == WeatherTypeHeavySnowShowerDay || w == WeatherTypeHeavySnowShowerNight:
+			hazards = append(hazards, Hazard{Kind: HazardSnow, Level: HazardLevelSevere, Value: severity})
+		case w.IsSnow() || w.IsSleet():
+			hazards = append(hazards, Hazard{Kind: HazardSnow, Level: HazardLevelModerate, Value: severity})
+		case w.IsHail():
+			hazards = append(hazards, Hazard{Kind: HazardHail, Level: HazardLevelModerate, Value: severity})
+		case w == WeatherTypeHeavyRain:
+			hazards = append(hazards, Hazard{Kind: HazardHeavyRain, Level: HazardLevelModerate, Value: severity})
+		case w == WeatherTypeHeavyRainShowerDay || w == WeatherTypeHeavyRainShowerNight:
+			hazards = append(hazards, Hazard{Kind: HazardHeavyRain, Level: HazardLevelMinor, Value: severity})
+		}
+	}
+
+	visibility, hasVisibility := f.Visibility()
+	switch {
+	case hasVisibility && visibility == VisibilityVeryPoor:
+		hazards = append(hazards, Hazard{Kind: HazardFog, Level: HazardLevelSevere, Value: float64(WeatherTypeFog.Severity())})
+	case hasVisibility && visibility == VisibilityPoor, hasWeather && w == WeatherTypeFog:
+		hazards = append(hazards, Hazard{Kind: HazardFog, Level: HazardLevelModerate, Value: float64(WeatherTypeFog.Severity())})
+	}
+
+	if frost, ok := f.FrostRisk(); ok && frost {
+		t, _ := f.temperatureCelsius()
+		level := HazardLevelModerate
+		if t <= 0 && hasWeather && w.IsPrecipitation() {
+			level = HazardLevelSevere
+		}
+		hazards = append(hazards, Hazard{Kind: HazardIce, Level: level, Value: -t})
+	}
+
+	if gust, ok := f.number(UnitMilesPerHour, KnownParameterWindGust, KnownParameterWindGustNoon, KnownParameterWindGustMidnight); ok {
+		switch {
+		case gust >= 60:
+			hazards = append(hazards, Hazard{Kind: HazardHighWind, Level: HazardLevelSevere, Value: gust})
+		case gust >= 50:
+			hazards = append(hazards, Hazard{Kind: HazardHighWind, Level: HazardLevelModerate, Value: gust})
+		case gust >= 40:
+			hazards = append(hazards, Hazard{Kind: HazardHighWind, Level: HazardLevelMinor, Value: gust})
+		}
+	}
+
+	sort.SliceStable(hazards, func(i, j int) bool {
+		if hazards[i].Level != hazards[j].Level {
+			return hazards[i].Level > hazards[j].Level
+		}
+		return hazards[i].Kind < hazards[j].Kind
+	})
+	return hazards
+}
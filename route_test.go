@@ -0,0 +1,148 @@
+package datapoint_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	dp "github.com/vitineth/datapoint"
+	"github.com/vitineth/datapoint/datapointtest"
+)
+
+func TestRouteForecast(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	sites := datapointtest.DefaultForecastSites()
+	exeter, plymouth, cardiff := sites[0], sites[1], sites[3]
+	index := dp.NewSpatialIndex(sites)
+	at := func(hour int, minute int) time.Time {
+		return time.Date(2024, time.March, 14, hour, minute, 0, 0, time.UTC)
+	}
+	points := []dp.Position{exeter.LatLon(), plymouth.LatLon(), cardiff.LatLon()}
+	options := dp.RouteOptions{ETAs: []time.Time{at(16, 30), at(19, 30), at(31, 30)}, MaxSegmentLength: 30000}
+
+	if _, err := client.RouteForecast(points, options, nil); err == nil {
+		t.Error("expected an error without a spatial index")
+	}
+
+	route, err := client.RouteForecast(points, options, index)
+	if err != nil {
+		t.Fatalf("failed to forecast route: %v", err)
+	}
+
+	// Exeter to Plymouth is split in two and Plymouth to Cardiff, which passes close to Exeter, in five
+	expected := []struct {
+		site      dp.Site
+		validFrom time.Time
+	}{
+		{exeter, at(15, 0)},
+		{plymouth, at(18, 0)},
+		{plymouth, at(18, 0)},
+		{exeter, at(21, 0)},
+		{exeter, at(24, 0)},
+		{cardiff, at(27, 0)},
+		{cardiff, at(30, 0)},
+	}
+	if len(route.Segments) != len(expected) {
+		t.Fatalf("expected %v segments but got %v", len(expected), len(route.Segments))
+	}
+	for i, segment := range route.Segments {
+		if segment.Site.Site.Id != expected[i].site.Id {
+			t.Errorf("expected segment %v to use %v but got %v", i, expected[i].site.Name, segment.Site.Site.Name)
+		}
+		if !segment.HasForecast || !segment.Forecast.ValidFrom.Equal(expected[i].validFrom) {
+			t.Errorf("expected segment %v to use the forecast from %v but got %v", i, expected[i].validFrom, segment.Forecast.ValidFrom)
+		}
+
+		rep, err := client.FiveDayForecast(dp.ResolutionThreeHourly, segment.Site.Site.Id, nil)
+		if err != nil {
+			t.Fatalf("failed to fetch forecast: %v", err)
+		}
+		f, _ := rep.ForecastAt(expected[i].validFrom)
+		if !reflect.DeepEqual(segment.Hazards, f.Hazards()) {
+			t.Errorf("expected segment %v to have hazards %v but got %v", i, f.Hazards(), segment.Hazards)
+		}
+	}
+
+	// the heavy rain on segments 3, 4 and 6 is reported once, on the first segment it reaches the worst level
+	worst := []dp.RouteHazard{
+		{Hazard: dp.Hazard{Kind: dp.HazardSnow, Level: dp.HazardLevelModerate, Value: float64(dp.WeatherTypeLightSnowShowerDay.Severity())}, Segment: 5},
+		{Hazard: dp.Hazard{Kind: dp.HazardFog, Level: dp.HazardLevelModerate, Value: float64(dp.WeatherTypeFog.Severity())}, Segment: 5},
+		{Hazard: dp.Hazard{Kind: dp.HazardHeavyRain, Level: dp.HazardLevelMinor, Value: float64(dp.WeatherTypeHeavyRainShowerDay.Severity())}, Segment: 3},
+	}
+	if !reflect.DeepEqual(route.WorstHazards, worst) {
+		t.Errorf("expected the worst hazards to be %v but got %v", worst, route.WorstHazards)
+	}
+}
+
+func TestRouteWithoutForecast(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// the retired site is nearest to the route but the service has no forecast for it
+	retired := dp.Site{Id: 1, Latitude: 50.6, Longitude: -3.8, Name: "Retired"}
+	index := dp.NewSpatialIndex(append(datapointtest.DefaultForecastSites(), retired))
+	points := []dp.Position{dp.LatLon{Latitude: 50.6, Longitude: -3.9}, dp.LatLon{Latitude: 50.6, Longitude: -3.7}}
+	route, err := client.RouteForecast(points, dp.RouteOptions{Departure: datapointtest.DefaultDataDate, Speed: 10}, index)
+	if err != nil {
+		t.Fatalf("failed to forecast route: %v", err)
+	}
+	if len(route.Segments) != 1 || route.Segments[0].Site.Site.Id != retired.Id || route.Segments[0].HasForecast {
+		t.Errorf("expected a single segment using the retired site without a forecast but got %+v", route.Segments)
+	}
+}
+
+func TestHazards(t *testing.T) {
+	forecast := func(temperature int, gust int, weather dp.WeatherType, visibility string) dp.Forecast {
+		return dp.Forecast{
+			IntParams: map[string]dp.IntParameterValue{
+				"T": {ParameterDescriptor: dp.ParameterDescriptor{Name: "T", Units: "C"}, Value: temperature},
+				"H": {ParameterDescriptor: dp.ParameterDescriptor{Name: "H", Units: "%"}, Value: 90},
+				"G": {ParameterDescriptor: dp.ParameterDescriptor{Name: "G", Units: "mph"}, Value: gust},
+				"W": {ParameterDescriptor: dp.ParameterDescriptor{Name: "W"}, Value: int(weather)},
+			},
+			StringParams: map[string]dp.StringParameterValue{
+				"V": {ParameterDescriptor: dp.ParameterDescriptor{Name: "V"}, Value: visibility},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		forecast dp.Forecast
+		expected []dp.Hazard
+	}{
+		{"benign", forecast(15, 20, dp.WeatherTypeSunnyDay, "VG"), nil},
+		{"thunder and gale", forecast(15, 55, dp.WeatherTypeThunder, "GO"), []dp.Hazard{
+			{Kind: dp.HazardThunder, Level: dp.HazardLevelSevere, Value: float64(dp.WeatherTypeThunder.Severity())},
+			{Kind: dp.HazardHighWind, Level: dp.HazardLevelModerate, Value: 55},
+		}},
+		{"freezing rain", forecast(-2, 30, dp.WeatherTypeLightRain, "MO"), []dp.Hazard{
+			{Kind: dp.HazardIce, Level: dp.HazardLevelSevere, Value: 2},
+		}},
+		{"heavy snow in fog", forecast(-1, 42, dp.WeatherTypeHeavySnow, "VP"), []dp.Hazard{
+			{Kind: dp.HazardSnow, Level: dp.HazardLevelSevere, Value: float64(dp.WeatherTypeHeavySnow.Severity())},
+			{Kind: dp.HazardIce, Level: dp.HazardLevelSevere, Value: 1},
+			{Kind: dp.HazardFog, Level: dp.HazardLevelSevere, Value: float64(dp.WeatherTypeFog.Severity())},
+			{Kind: dp.HazardHighWind, Level: dp.HazardLevelMinor, Value: 42},
+		}},
+		{"rain near freezing", forecast(1, 10, dp.WeatherTypeLightRain, "GO"), []dp.Hazard{
+			{Kind: dp.HazardIce, Level: dp.HazardLevelModerate, Value: -1},
+		}},
+		{"humid and above freezing", forecast(2, 10, dp.WeatherTypeClearNight, "GO"), nil},
+	}
+	for _, test := range tests {
+		if actual := test.forecast.Hazards(); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%v: expected %v but got %v", test.name, test.expected, actual)
+		}
+	}
+}
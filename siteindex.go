@@ -0,0 +1,311 @@
+package datapoint
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SiteIndex supports looking up sites from a site list by ID, searching them by name and grouping them by Region and
+// UnitaryAuthArea
+type SiteIndex struct {
+	sites []Site
+	byId  map[int]int
+	// names holds the normalised name of each site, and qualified the normalised name followed by its unitary auth area
+	names     []string
+	qualified []string
+	// nameCounts holds the number of sites sharing each normalised name
+	nameCounts map[string]int
+}
+
+// SiteMatch is a site returned by SiteIndex.Search along with how well it matched the query
+type SiteMatch struct {
+	Site Site
+	// Score is between 0 and 1, where 1 is an exact match of the name
+	Score float64
+}
+
+// minimumSimilarity is the lowest edit distance similarity between a query and a name for the name to be considered
+// a misspelling of the query
+const minimumSimilarity = 0.7
+
+// NewSiteIndex builds an index over the sites. The slice is copied
+func NewSiteIndex(sites []Site) *SiteIndex {
+	index := &SiteIndex{
+		sites:      append([]Site(nil), sites...),
+		byId:       make(map[int]int, len(sites)),
+		names:      make([]string, len(sites)),
+		qualified:  make([]string, len(sites)),
+		nameCounts: map[string]int{},
+	}
+	for i, site := range index.sites {
+		index.byId[site.Id] = i
+		index.names[i] = normaliseName(site.Name)
+		index.qualified[i] = strings.TrimSpace(index.names[i] + " " + normaliseName(site.UnitaryAuthArea))
+		index.nameCounts[index.names[i]]++
+	}
+	return index
+}
+
+// Sites returns every site in the index in the order they were given
+func (s *SiteIndex) Sites() []Site {
+	return append([]Site(nil), s.sites...)
+}
+
+// Len returns the number of sites in the index
+func (s *SiteIndex) Len() int {
+	return len(s.sites)
+}
+
+// ById returns the site with the ID. The second value is false if there is no such site
+func (s *SiteIndex) ById(id int) (Site, bool) {
+	i, ok := s.byId[id]
+	if !ok {
+		return Site{}, false
+	}
+	return s.sites[i], true
+}
+
+// Search returns the sites whose names match the query, best match first. Matching ignores case, accents and
+// punctuation, and accepts prefixes, whole words within a name and small misspellings. A query may include the
+// unitary auth area to pick between sites with the same name, e.g. 'Newport, Isle of Wight'. If limit is greater than
+// zero at most that many matches are returned
+func (s *SiteIndex) Search(query string, limit int) []SiteMatch {
+	q := normaliseName(query)
+	if q == "" {
+		return nil
+	}
+
+	var matches []SiteMatch
+	for i, site := range s.sites {
+		score := max(matchScore(q, s.names[i]), matchScore(q, s.qualified[i]))
+		if score > 0 {
+			matches = append(matches, SiteMatch{Site: site, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if len(a.Site.Name) != len(b.Site.Name) {
+			return len(a.Site.Name) < len(b.Site.Name)
+		}
+		if a.Site.Name != b.Site.Name {
+			return a.Site.Name < b.Site.Name
+		}
+		return a.Site.Id < b.Site.Id
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// IsAmbiguous returns if more than one site in the index shares the name of the site
+func (s *SiteIndex) IsAmbiguous(site Site) bool {
+	return s.nameCounts[normaliseName(site.Name)] > 1
+}
+
+// WithName returns every site whose name matches exactly, ignoring case, accents and punctuation
+func (s *SiteIndex) WithName(name string) []Site {
+	n := normaliseName(name)
+	var result []Site
+	for i, site := range s.sites {
+		if s.names[i] == n {
+			result = append(result, site)
+		}
+	}
+	return result
+}
+
+// DisplayName returns the name of the site, followed by its unitary auth area if another site in the index has the
+// same name, e.g. 'Newport, Isle of Wight'. If the unitary auth area does not tell the sites apart the ID is used
+func (s *SiteIndex) DisplayName(site Site) string {
+	if !s.IsAmbiguous(site) {
+		return site.Name
+	}
+
+	if site.UnitaryAuthArea != "" {
+		qualified := strings.TrimSpace(normaliseName(site.Name) + " " + normaliseName(site.UnitaryAuthArea))
+		count := 0
+		for _, q := range s.qualified {
+			if q == qualified {
+				count++
+			}
+		}
+		if count == 1 {
+			return site.Name + ", " + site.UnitaryAuthArea
+		}
+	}
+	return site.Name + " (" + strconv.Itoa(site.Id) + ")"
+}
+
+// Regions returns the distinct regions of the sites in the index, sorted
+func (s *SiteIndex) Regions() []string {
+	return distinctSorted(s.sites, func(site Site) string { return site.Region })
+}
+
+// UnitaryAuthAreas returns the distinct unitary auth areas of the sites in the index, sorted
+func (s *SiteIndex) UnitaryAuthAreas() []string {
+	return distinctSorted(s.sites, func(site Site) string { return site.UnitaryAuthArea })
+}
+
+// ByRegion groups the sites in the index by their region
+func (s *SiteIndex) ByRegion() map[string][]Site {
+	return groupSites(s.sites, func(site Site) string { return site.Region })
+}
+
+// ByUnitaryAuthArea groups the sites in the index by their unitary auth area
+func (s *SiteIndex) ByUnitaryAuthArea() map[string][]Site {
+	return groupSites(s.sites, func(site Site) string { return site.UnitaryAuthArea })
+}
+
+// InRegion returns a new index containing only the sites in the region, compared without regard to case
+func (s *SiteIndex) InRegion(region string) *SiteIndex {
+	return s.Filter(func(site Site) bool { return strings.EqualFold(site.Region, region) })
+}
+
+// InUnitaryAuthArea returns a new index containing only the sites in the unitary auth area, compared ignoring case,
+// accents and punctuation
+func (s *SiteIndex) InUnitaryAuthArea(area string) *SiteIndex {
+	a := normaliseName(area)
+	return s.Filter(func(site Site) bool { return normaliseName(site.UnitaryAuthArea) == a })
+}
+
+// Filter returns a new index containing only the sites for which keep returns true
+func (s *SiteIndex) Filter(keep func(Site) bool) *SiteIndex {
+	var sites []Site
+	for _, site := range s.sites {
+		if keep(site) {
+			sites = append(sites, site)
+		}
+	}
+	return NewSiteIndex(sites)
+}
+
+func distinctSorted(sites []Site, key func(Site) string) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, site := range sites {
+		k := key(site)
+		if k != "" && !seen[k] {
+			seen[k] = true
+			result = append(result, k)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func groupSites(sites []Site, key func(Site) string) map[string][]Site {
+	result := map[string][]Site{}
+	for _, site := range sites {
+		k := key(site)
+		result[k] = append(result[k], site)
+	}
+	return result
+}
+
+// matchScore scores how well the normalised query matches the normalised name, returning 0 if it does not
+func matchScore(query string, name string) float64 {
+	if name == "" {
+		return 0
+	}
+	coverage := float64(len(query)) / float64(max(len(name), len(query)))
+	switch {
+	case query == name:
+		return 1
+	case strings.HasPrefix(name, query):
+		return 0.8 + 0.1*coverage
+	case strings.Contains(" "+name, " "+query):
+		return 0.7 + 0.1*coverage
+	case strings.Contains(name, query):
+		return 0.6 + 0.1*coverage
+	}
+
+	// allow for misspellings of the whole name or of its leading words
+	best := similarity(query, name)
+	words := strings.Fields(name)
+	for n := 1; n < len(words); n++ {
+		best = max(best, similarity(query, strings.Join(words[:n], " ")))
+	}
+	if best < minimumSimilarity {
+		return 0
+	}
+	return 0.6 * best
+}
+
+// similarity returns 1 minus the Levenshtein distance between a and b divided by the length of the longer
+func similarity(a string, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return 1 - float64(previous[len(rb)])/float64(max(len(ra), len(rb)))
+}
+
+// accentFolds maps accented Latin letters, including those used in Welsh, Irish and Scottish Gaelic place names, onto
+// their unaccented form
+var accentFolds = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'ç': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+	'ñ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'œ': "oe",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u",
+	'ŵ': "w", 'ẁ': "w", 'ẃ': "w", 'ẅ': "w",
+	'ý': "y", 'ÿ': "y", 'ŷ': "y", 'ỳ': "y",
+	'ß': "ss",
+}
+
+// normaliseName lower cases the name, removes accents and replaces punctuation with single spaces so that names can be
+// compared loosely, e.g. "St. Mary's" and "st marys" both become "st marys"
+func normaliseName(name string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(name) {
+		if fold, ok := accentFolds[r]; ok {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteString(fold)
+			continue
+		}
+		switch {
+		case r == '\'' || r == '’':
+			// apostrophes join the letters either side rather than splitting words
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		default:
+			space = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,170 @@
+package datapoint
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+//go:generate go run ./internal/snapshotgen -out snapshot
+
+// Names of the embedded snapshot files within the snapshot directory
+const (
+	ForecastSnapshotFile    = "forecast-sites.json"
+	ObservationSnapshotFile = "observation-sites.json"
+)
+
+//go:embed snapshot/*.json
+var snapshotFiles embed.FS
+
+// SiteSnapshot is a copy of a site list which is embedded in the package so it can be used without calling the
+// service. Snapshots are refreshed by running go generate with DATAPOINT_API_KEY set. Until they have been generated
+// the embedded files are empty and ErrSnapshotNotGenerated is returned
+type SiteSnapshot struct {
+	// GeneratedAt is when the snapshot was fetched from the service
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Sites is the site list at the time the snapshot was taken
+	Sites []Site `json:"sites"`
+}
+
+var (
+	forecastSnapshot    = sync.OnceValues(func() (SiteSnapshot, error) { return loadSnapshot(ForecastSnapshotFile) })
+	observationSnapshot = sync.OnceValues(func() (SiteSnapshot, error) { return loadSnapshot(ObservationSnapshotFile) })
+)
+
+func loadSnapshot(name string) (SiteSnapshot, error) {
+	data, err := snapshotFiles.ReadFile("snapshot/" + name)
+	if err != nil {
+		return SiteSnapshot{}, fmt.Errorf("failed to read snapshot %v: %w", name, err)
+	}
+	var snapshot SiteSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return SiteSnapshot{}, fmt.Errorf("failed to parse snapshot %v: %w", name, err)
+	}
+	return snapshot, nil
+}
+
+// ErrSnapshotNotGenerated is returned when the embedded snapshot has not yet been generated from the service
+var ErrSnapshotNotGenerated = errors.New("site list snapshot has not been generated, run go generate with DATAPOINT_API_KEY set")
+
+// ForecastSiteSnapshot returns the embedded snapshot of ForecastSiteList
+func ForecastSiteSnapshot() (SiteSnapshot, error) {
+	return copySnapshot(forecastSnapshot())
+}
+
+// ObservationSiteSnapshot returns the embedded snapshot of ObservationSiteList
+func ObservationSiteSnapshot() (SiteSnapshot, error) {
+	return copySnapshot(observationSnapshot())
+}
+
+func copySnapshot(snapshot SiteSnapshot, err error) (SiteSnapshot, error) {
+	if err != nil {
+		return SiteSnapshot{}, err
+	}
+	if snapshot.GeneratedAt.IsZero() {
+		return SiteSnapshot{}, ErrSnapshotNotGenerated
+	}
+	snapshot.Sites = append([]Site(nil), snapshot.Sites...)
+	return snapshot, nil
+}
+
+// siteListCache holds a site list which starts as a snapshot and is replaced by the live list once a background
+// refresh succeeds
+type siteListCache struct {
+	load       func() (SiteSnapshot, error)
+	mu         sync.Mutex
+	sites      []Site
+	loaded     bool
+	refreshed  bool
+	refreshing bool
+	done       chan struct{}
+}
+
+type siteListSnapshot struct {
+	forecast    func() (SiteSnapshot, error)
+	observation func() (SiteSnapshot, error)
+}
+
+func (s siteListSnapshot) apply(client *DataPointClient) {
+	client.siteLists = map[string]*siteListCache{
+		"wxfcs": {load: s.forecast},
+		"wxobs": {load: s.observation},
+	}
+}
+
+// WithSiteListSnapshot makes ForecastSiteList and ObservationSiteList return the embedded snapshots straight away,
+// without using any quota, while the live lists are fetched in the background. Once a live list has been fetched it is
+// returned instead. If fetching fails the snapshot continues to be used and the fetch is retried on the next call. If
+// a snapshot has not been generated the first call fetches the live list and fails if it cannot be fetched
+func WithSiteListSnapshot() Opt {
+	return siteListSnapshot{forecast: ForecastSiteSnapshot, observation: ObservationSiteSnapshot}
+}
+
+// WithSiteListSnapshotFrom behaves like WithSiteListSnapshot but starts from the snapshots provided, such as ones
+// saved by a previous run, instead of those embedded in the package
+func WithSiteListSnapshotFrom(forecast SiteSnapshot, observation SiteSnapshot) Opt {
+	return siteListSnapshot{
+		forecast:    func() (SiteSnapshot, error) { return copySnapshot(forecast, nil) },
+		observation: func() (SiteSnapshot, error) { return copySnapshot(observation, nil) },
+	}
+}
+
+// cachedSiteList returns the current site list for the id and starts a background refresh if one has not succeeded
+func (d *DataPointClient) cachedSiteList(id string, cache *siteListCache) ([]Site, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if !cache.loaded {
+		snapshot, err := cache.load()
+		if errors.Is(err, ErrSnapshotNotGenerated) {
+			// without a snapshot there is nothing to return until the live list has been fetched
+			sites, err := d.siteList(id)
+			if err != nil {
+				return nil, err
+			}
+			cache.sites, cache.loaded, cache.refreshed = sites, true, true
+			return append([]Site(nil), sites...), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		cache.sites = snapshot.Sites
+		cache.loaded = true
+	}
+
+	if !cache.refreshed && !cache.refreshing {
+		cache.refreshing = true
+		cache.done = make(chan struct{})
+		go d.refreshSiteList(id, cache)
+	}
+	return append([]Site(nil), cache.sites...), nil
+}
+
+func (d *DataPointClient) refreshSiteList(id string, cache *siteListCache) {
+	sites, err := d.siteList(id)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if err == nil {
+		cache.sites = sites
+		cache.refreshed = true
+	}
+	cache.refreshing = false
+	close(cache.done)
+}
+
+// WaitForSiteLists blocks until any background refresh of the site lists started by WithSiteListSnapshot has finished.
+// It returns immediately if the option was not used or no refresh is running
+func (d *DataPointClient) WaitForSiteLists() {
+	for _, cache := range d.siteLists {
+		cache.mu.Lock()
+		done, refreshing := cache.done, cache.refreshing
+		cache.mu.Unlock()
+		if refreshing {
+			<-done
+		}
+	}
+}
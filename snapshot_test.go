@@ -0,0 +1,103 @@
+package datapoint_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	dp "github.com/vitineth/datapoint"
+	"github.com/vitineth/datapoint/datapointtest"
+)
+
+func TestEmbeddedSnapshots(t *testing.T) {
+	for name, load := range map[string]func() (dp.SiteSnapshot, error){
+		"forecast":    dp.ForecastSiteSnapshot,
+		"observation": dp.ObservationSiteSnapshot,
+	} {
+		snapshot, err := load()
+		if errors.Is(err, dp.ErrSnapshotNotGenerated) {
+			continue
+		}
+		if err != nil {
+			t.Errorf("failed to load the %v snapshot: %v", name, err)
+		} else if len(snapshot.Sites) == 0 {
+			t.Errorf("expected the generated %v snapshot to contain sites", name)
+		}
+	}
+}
+
+func TestSiteListSnapshot(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+
+	stale := dp.SiteSnapshot{
+		GeneratedAt: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Sites:       datapointtest.DefaultForecastSites()[:1],
+	}
+	client, err := server.Client(dp.WithSiteListSnapshotFrom(stale, stale))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// the snapshot is returned straight away while the live list is fetched in the background
+	server.InjectFault("val/wxfcs/all/json/sitelist", datapointtest.Fault{Status: http.StatusServiceUnavailable, Times: 1})
+	sites, err := client.ForecastSiteList()
+	if err != nil || len(sites) != 1 {
+		t.Fatalf("expected the snapshot to be returned but got %v sites, %v", len(sites), err)
+	}
+	client.WaitForSiteLists()
+
+	// a failed refresh leaves the snapshot in place and is retried on the next call
+	sites, err = client.ForecastSiteList()
+	if err != nil || len(sites) != 1 {
+		t.Fatalf("expected the snapshot to be kept after a failed refresh but got %v sites, %v", len(sites), err)
+	}
+	client.WaitForSiteLists()
+
+	sites, err = client.ForecastSiteList()
+	if err != nil || len(sites) != len(datapointtest.DefaultForecastSites()) {
+		t.Fatalf("expected the live list once refreshed but got %v sites, %v", len(sites), err)
+	}
+	requests := server.Requests()
+	if requests != 2 {
+		t.Errorf("expected one failed and one successful refresh but the server received %v requests", requests)
+	}
+
+	// once refreshed the live list is reused without further requests
+	if _, err := client.ForecastSiteList(); err != nil || server.Requests() != requests {
+		t.Errorf("expected the refreshed list to be reused but got %v after %v requests", err, server.Requests())
+	}
+
+	// the observation list is cached separately
+	sites, err = client.ObservationSiteList()
+	if err != nil || len(sites) != 1 {
+		t.Errorf("expected the observation snapshot to be returned but got %v sites, %v", len(sites), err)
+	}
+	client.WaitForSiteLists()
+	sites, err = client.ObservationSiteList()
+	if err != nil || len(sites) != len(datapointtest.DefaultObservationSites()) {
+		t.Errorf("expected the live observation list once refreshed but got %v sites, %v", len(sites), err)
+	}
+}
+
+func TestSiteListSnapshotNotGenerated(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	client, err := server.Client(dp.WithSiteListSnapshotFrom(dp.SiteSnapshot{}, dp.SiteSnapshot{}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// without a snapshot the live list must be fetched before anything can be returned
+	server.SetQuota(0)
+	if _, err := client.ForecastSiteList(); err == nil {
+		t.Error("expected an error when there is no snapshot and the live list cannot be fetched")
+	}
+
+	server.SetQuota(-1)
+	sites, err := client.ForecastSiteList()
+	if err != nil || len(sites) != len(datapointtest.DefaultForecastSites()) {
+		t.Errorf("expected the live list without a snapshot but got %v sites, %v", len(sites), err)
+	}
+}
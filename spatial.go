@@ -0,0 +1,211 @@
+package datapoint
+
+import (
+	"math"
+	"sort"
+)
+
+// EarthRadius is the mean radius of the Earth in metres used for great-circle distances
+const EarthRadius = 6371008.8
+
+// LatLon is a position in decimal degrees on the WGS84 datum, as used by DataPoint
+type LatLon struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// LatLon returns the position of the site
+func (s Site) LatLon() LatLon {
+	return LatLon{Latitude: s.Latitude, Longitude: s.Longitude}
+}
+
+// Distance returns the great-circle distance between two positions in metres, using the haversine formula
+func Distance(from Position, to Position) float64 {
+	a, b := from.WGS84(), to.WGS84()
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Sqrt(min(h, 1)))
+}
+
+// DistanceTo returns the great-circle distance from the site to a position in metres
+func (s Site) DistanceTo(position Position) float64 {
+	return Distance(s.LatLon(), position)
+}
+
+// SiteDistance is a site returned from a SpatialIndex query along with its distance from the queried position
+type SiteDistance struct {
+	Site Site
+	// Distance is the great-circle distance in metres
+	Distance float64
+}
+
+// SpatialIndex answers nearest-site queries over a site list, such as the result of ForecastSiteList or
+// ObservationSiteList. Sites are held in a k-d tree over points on the unit sphere so that straight line (chord)
+// distances order sites in the same way as great-circle distances
+type SpatialIndex struct {
+	nodes []spatialNode
+}
+
+type spatialNode struct {
+	site  Site
+	point [3]float64
+	axis  int
+	// left and right are indices into nodes, or -1 if there is no child
+	left  int
+	right int
+}
+
+// NewSpatialIndex builds an index over the sites. The slice is not retained
+func NewSpatialIndex(sites []Site) *SpatialIndex {
+	nodes := make([]spatialNode, len(sites))
+	for i, site := range sites {
+		nodes[i] = spatialNode{site: site, point: unitVector(site.LatLon())}
+	}
+
+	index := &SpatialIndex{nodes: make([]spatialNode, 0, len(nodes))}
+	index.build(nodes, 0)
+	return index
+}
+
+// build adds the nodes to the tree in place, returning the index of the root of the subtree or -1 if nodes is empty
+func (s *SpatialIndex) build(nodes []spatialNode, depth int) int {
+	if len(nodes) == 0 {
+		return -1
+	}
+
+	axis := depth % 3
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].point[axis] < nodes[j].point[axis]
+	})
+	median := len(nodes) / 2
+
+	node := nodes[median]
+	node.axis = axis
+	s.nodes = append(s.nodes, node)
+	position := len(s.nodes) - 1
+
+	left := s.build(nodes[:median], depth+1)
+	right := s.build(nodes[median+1:], depth+1)
+	s.nodes[position].left = left
+	s.nodes[position].right = right
+	return position
+}
+
+// Len returns the number of sites in the index
+func (s *SpatialIndex) Len() int {
+	return len(s.nodes)
+}
+
+// Nearest returns the closest site to the position. The second value is false if the index is empty
+func (s *SpatialIndex) Nearest(position Position) (SiteDistance, bool) {
+	result := s.NearestK(position, 1)
+	if len(result) == 0 {
+		return SiteDistance{}, false
+	}
+	return result[0], true
+}
+
+// NearestK returns up to k sites closest to the position, nearest first. The position may be given in any form, such
+// as a LatLon or an OSGB36 grid position
+func (s *SpatialIndex) NearestK(position Position, k int) []SiteDistance {
+	if k <= 0 || len(s.nodes) == 0 {
+		return nil
+	}
+
+	p := position.WGS84()
+	target := unitVector(p)
+	var best []int
+	var bestDistances []float64
+
+	var search func(i int)
+	search = func(i int) {
+		if i < 0 {
+			return
+		}
+		node := &s.nodes[i]
+		d := chordSquared(node.point, target)
+		if len(best) < k || d < bestDistances[len(best)-1] {
+			at := sort.SearchFloat64s(bestDistances, d)
+			if len(best) < k {
+				best = append(best, 0)
+				bestDistances = append(bestDistances, 0)
+			}
+			copy(best[at+1:], best[at:])
+			copy(bestDistances[at+1:], bestDistances[at:])
+			best[at] = i
+			bestDistances[at] = d
+		}
+
+		delta := target[node.axis] - node.point[node.axis]
+		near, far := node.left, node.right
+		if delta > 0 {
+			near, far = far, near
+		}
+		search(near)
+		if len(best) < k || delta*delta < bestDistances[len(best)-1] {
+			search(far)
+		}
+	}
+	search(0)
+
+	result := make([]SiteDistance, len(best))
+	for i, n := range best {
+		site := s.nodes[n].site
+		result[i] = SiteDistance{Site: site, Distance: Distance(site.LatLon(), p)}
+	}
+	return result
+}
+
+// WithinRadius returns every site within radius metres of the position, nearest first
+func (s *SpatialIndex) WithinRadius(position Position, radius float64) []SiteDistance {
+	if radius < 0 || len(s.nodes) == 0 {
+		return nil
+	}
+
+	p := position.WGS84()
+	target := unitVector(p)
+	// the chord subtending the radius on the unit sphere, capped at the diameter for radii beyond the antipode
+	chord := 2 * math.Sin(math.Min(radius/EarthRadius, math.Pi)/2)
+	limit := chord * chord
+
+	var result []SiteDistance
+	var search func(i int)
+	search = func(i int) {
+		if i < 0 {
+			return
+		}
+		node := &s.nodes[i]
+		if chordSquared(node.point, target) <= limit {
+			distance := Distance(node.site.LatLon(), p)
+			if distance <= radius {
+				result = append(result, SiteDistance{Site: node.site, Distance: distance})
+			}
+		}
+
+		delta := target[node.axis] - node.point[node.axis]
+		if delta <= 0 || delta*delta <= limit {
+			search(node.left)
+		}
+		if delta >= 0 || delta*delta <= limit {
+			search(node.right)
+		}
+	}
+	search(0)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Distance < result[j].Distance
+	})
+	return result
+}
+
+func unitVector(position LatLon) [3]float64 {
+	lat, lon := position.Latitude*math.Pi/180, position.Longitude*math.Pi/180
+	return [3]float64{math.Cos(lat) * math.Cos(lon), math.Cos(lat) * math.Sin(lon), math.Sin(lat)}
+}
+
+func chordSquared(a [3]float64, b [3]float64) float64 {
+	dx, dy, dz := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return dx*dx + dy*dy + dz*dz
+}
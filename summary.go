@@ -0,0 +1,214 @@
+package datapoint
+
+import (
+	"sort"
+	"time"
+)
+
+// DailySummary summarises the weather of a single date in Europe/London. Summaries built from a daily forecast and
+// from a three hourly forecast have the same meaning, so they can be compared directly
+type DailySummary struct {
+	// Date is midnight at the start of the date in Europe/London
+	Date time.Time
+	// DayComplete and NightComplete are true if the forecasts cover the whole of the day segment and the night segment
+	// which starts on this date. A three hourly forecast usually starts part way through its first date and ends part
+	// way through its last, in which case the values below only describe the part which is covered
+	DayComplete   bool
+	NightComplete bool
+	// MaxTemperature is the highest temperature in °C during the day segment, matching the Dm parameter of a daily
+	// forecast. It is nil unless DayComplete is true
+	MaxTemperature *int
+	// MinTemperature is the lowest temperature in °C during the night segment which starts on this date, matching
+	// the Nm parameter of a daily forecast. It is nil unless NightComplete is true
+	MinTemperature *int
+	// MaxGust is the highest wind gust in mph across the day and night
+	MaxGust *int
+	// PeakPrecipitationProbability is the highest precipitation probability in percent across the day and night
+	PeakPrecipitationProbability *int
+	// DominantWeather is the most common weather type during the day segment, or the night segment if the day is not
+	// covered. Ties are won by the most severe type
+	DominantWeather WeatherType
+	// WorstWeather is the weather type with the highest severity across the day and night
+	WorstWeather WeatherType
+	// PeakUv is the highest UV index across the day and night
+	PeakUv *UvIndex
+}
+
+type dailyGroup struct {
+	date  time.Time
+	day   []Forecast
+	night []Forecast
+}
+
+// DailySummaries summarises the forecast for each date in Europe/London, in date order. Daily forecasts are summarised
+// from their day and night segments. Three hourly forecasts are assigned to the segment containing the middle of the
+// period they are valid for, using the same 06:00 and 18:00 boundaries as the daily feed, so a night runs into the
+// morning of the following date
+func (s SiteRep) DailySummaries() []DailySummary {
+	groups := map[time.Time]*dailyGroup{}
+	for _, period := range s.Location.Period {
+		for _, f := range period.Forecasts {
+			date, segment := f.dailySegment()
+			if segment == SegmentNone {
+				continue
+			}
+			group, ok := groups[date]
+			if !ok {
+				group = &dailyGroup{date: date}
+				groups[date] = group
+			}
+			if segment == SegmentDay {
+				group.day = append(group.day, f)
+			} else {
+				group.night = append(group.night, f)
+			}
+		}
+	}
+
+	var all []Forecast
+	for _, period := range s.Location.Period {
+		all = append(all, period.Forecasts...)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].ValidFrom.Before(all[j].ValidFrom)
+	})
+
+	summaries := make([]DailySummary, 0, len(groups))
+	for _, group := range groups {
+		summary := group.summarise()
+		summary.DayComplete = covers(all, SegmentDay, group.date)
+		summary.NightComplete = covers(all, SegmentNight, group.date)
+		if !summary.DayComplete {
+			summary.MaxTemperature = nil
+		}
+		if !summary.NightComplete {
+			summary.MinTemperature = nil
+		}
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Date.Before(summaries[j].Date)
+	})
+	return summaries
+}
+
+// dailySegment returns the date in Europe/London and the segment the forecast belongs to
+func (f Forecast) dailySegment() (time.Time, Segment) {
+	if f.Segment != SegmentNone {
+		y, m, d := f.ValidFrom.In(London).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, London), f.Segment
+	}
+	if f.ValidFrom.IsZero() {
+		return time.Time{}, SegmentNone
+	}
+
+	middle := f.ValidFrom.Add(f.ValidTo.Sub(f.ValidFrom) / 2).In(London)
+	y, m, d := middle.Date()
+	switch {
+	case middle.Hour() < dayStartHour:
+		return time.Date(y, m, d-1, 0, 0, 0, 0, London), SegmentNight
+	case middle.Hour() < nightStartHour:
+		return time.Date(y, m, d, 0, 0, 0, 0, London), SegmentDay
+	default:
+		return time.Date(y, m, d, 0, 0, 0, 0, London), SegmentNight
+	}
+}
+
+// covers returns if the forecasts, which must be sorted by ValidFrom, are valid for the whole of the segment on the
+// date without any gaps
+func covers(forecasts []Forecast, segment Segment, date time.Time) bool {
+	from, to := segment.Window(date)
+	for _, f := range forecasts {
+		if !f.ValidTo.After(from) {
+			continue
+		}
+		if f.ValidFrom.After(from) {
+			return false
+		}
+		from = f.ValidTo
+		if !from.Before(to) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g dailyGroup) summarise() DailySummary {
+	summary := DailySummary{
+		Date:            g.date,
+		DominantWeather: WeatherTypeNotAvailable,
+		WorstWeather:    WeatherTypeNotAvailable,
+	}
+
+	for _, f := range g.day {
+		if t, ok := f.Temperature(); ok {
+			summary.MaxTemperature = maxOf(summary.MaxTemperature, t.Value)
+		}
+	}
+	for _, f := range g.night {
+		if t, ok := f.Temperature(); ok {
+			if summary.MinTemperature == nil || t.Value < *summary.MinTemperature {
+				summary.MinTemperature = &t.Value
+			}
+		}
+	}
+
+	all := append(append([]Forecast(nil), g.day...), g.night...)
+	for i, f := range all {
+		if v, ok := f.WindGust(); ok {
+			summary.MaxGust = maxOf(summary.MaxGust, v.Value)
+		}
+		if v, ok := f.PrecipitationProbability(); ok {
+			summary.PeakPrecipitationProbability = maxOf(summary.PeakPrecipitationProbability, v.Value)
+		}
+		if v, ok := f.UvIndex(); ok {
+			if summary.PeakUv == nil || v > *summary.PeakUv {
+				summary.PeakUv = &v
+			}
+		}
+		if w, ok := f.WeatherType(); ok && w.IsKnown() && w.Severity() >= 0 {
+			if i < len(g.day) {
+				w = w.Day()
+			} else {
+				w = w.Night()
+			}
+			if !summary.WorstWeather.IsKnown() || summary.WorstWeather.Severity() < 0 || w.Severity() > summary.WorstWeather.Severity() {
+				summary.WorstWeather = w
+			}
+		}
+	}
+
+	summary.DominantWeather = dominantWeather(g.day, WeatherType.Day)
+	if summary.DominantWeather == WeatherTypeNotAvailable {
+		summary.DominantWeather = dominantWeather(g.night, WeatherType.Night)
+	}
+	return summary
+}
+
+// dominantWeather returns the most common weather type of the forecasts after converting each to the variant for the
+// segment, preferring the most severe when tied
+func dominantWeather(forecasts []Forecast, variant func(WeatherType) WeatherType) WeatherType {
+	counts := map[WeatherType]int{}
+	for _, f := range forecasts {
+		if w, ok := f.WeatherType(); ok && w.IsKnown() && w.Severity() >= 0 {
+			counts[variant(w)]++
+		}
+	}
+
+	result := WeatherTypeNotAvailable
+	for w, count := range counts {
+		best := counts[result]
+		if count > best || (count == best && w.Severity() > result.Severity()) ||
+			(count == best && w.Severity() == result.Severity() && w < result) {
+			result = w
+		}
+	}
+	return result
+}
+
+func maxOf(current *int, value int) *int {
+	if current == nil || value > *current {
+		return &value
+	}
+	return current
+}
@@ -0,0 +1,119 @@
+package datapoint_test
+
+import (
+	"testing"
+	"time"
+
+	dp "github.com/vitineth/datapoint"
+	"github.com/vitineth/datapoint/datapointtest"
+)
+
+func TestDailySummariesDaily(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	rep, err := client.FiveDayForecast(dp.ResolutionDaily, datapointtest.DefaultForecastSites()[0].Id, nil)
+	if err != nil {
+		t.Fatalf("failed to fetch forecast: %v", err)
+	}
+	maxima, _ := rep.TimeSeries(string(dp.KnownParameterDayMaximumTemperature))
+	minima, _ := rep.TimeSeries(string(dp.KnownParameterNightMinimumTemperature))
+
+	summaries := rep.DailySummaries()
+	if len(summaries) != len(maxima.Points) || len(summaries) != len(minima.Points) {
+		t.Fatalf("expected a summary for each of the %v days but got %v", len(maxima.Points), len(summaries))
+	}
+	for i, s := range summaries {
+		if !s.DayComplete || !s.NightComplete {
+			t.Errorf("expected %v to be complete but got day %v and night %v", s.Date, s.DayComplete, s.NightComplete)
+		}
+		if s.MaxTemperature == nil || float64(*s.MaxTemperature) != maxima.Points[i].Value {
+			t.Errorf("expected the maximum on %v to be Dm %v but got %v", s.Date, maxima.Points[i].Value, s.MaxTemperature)
+		}
+		if s.MinTemperature == nil || float64(*s.MinTemperature) != minima.Points[i].Value {
+			t.Errorf("expected the minimum on %v to be Nm %v but got %v", s.Date, minima.Points[i].Value, s.MinTemperature)
+		}
+	}
+}
+
+func TestDailySummariesThreeHourly(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	rep, err := client.FiveDayForecast(dp.ResolutionThreeHourly, datapointtest.DefaultForecastSites()[0].Id, nil)
+	if err != nil {
+		t.Fatalf("failed to fetch forecast: %v", err)
+	}
+
+	// the forecast runs from 15:00 on the first date, part way through the day, to midnight on the last date, part
+	// way through the night
+	date := func(day int) time.Time {
+		return time.Date(2024, time.March, day, 0, 0, 0, 0, dp.London)
+	}
+	expected := []struct {
+		date     time.Time
+		day      bool
+		night    bool
+		max, min *int
+	}{
+		{date(14), false, true, nil, intPtr(7)},
+		{date(15), true, true, intPtr(17), intPtr(8)},
+		{date(16), true, true, intPtr(18), intPtr(6)},
+		{date(17), true, true, intPtr(18), intPtr(7)},
+		{date(18), true, false, intPtr(16), nil},
+	}
+
+	summaries := rep.DailySummaries()
+	if len(summaries) != len(expected) {
+		t.Fatalf("expected %v summaries but got %v", len(expected), len(summaries))
+	}
+	for i, s := range summaries {
+		e := expected[i]
+		if !s.Date.Equal(e.date) || s.DayComplete != e.day || s.NightComplete != e.night {
+			t.Errorf("expected %v with day %v and night %v but got %v with day %v and night %v", e.date, e.day, e.night, s.Date, s.DayComplete, s.NightComplete)
+		}
+		if !equalIntPtr(s.MaxTemperature, e.max) || !equalIntPtr(s.MinTemperature, e.min) {
+			t.Errorf("expected %v to have a maximum of %v and minimum of %v but got %v and %v", e.date, fmtIntPtr(e.max), fmtIntPtr(e.min), fmtIntPtr(s.MaxTemperature), fmtIntPtr(s.MinTemperature))
+		}
+		// values other than the temperatures are still reported for the part of the date which is covered
+		if s.MaxGust == nil || s.PeakPrecipitationProbability == nil {
+			t.Errorf("expected %v to have a gust and precipitation probability", e.date)
+		}
+	}
+
+	// removing the 09:00 forecast leaves a gap in the day segment of the second date
+	period := &rep.Location.Period[1]
+	for i, f := range period.Forecasts {
+		if f.ValidFrom.Equal(time.Date(2024, time.March, 15, 9, 0, 0, 0, time.UTC)) {
+			period.Forecasts = append(period.Forecasts[:i:i], period.Forecasts[i+1:]...)
+			break
+		}
+	}
+	s := rep.DailySummaries()[1]
+	if s.DayComplete || s.MaxTemperature != nil || !s.NightComplete {
+		t.Errorf("expected the day segment with a gap to be incomplete but got day %v with a maximum of %v and night %v", s.DayComplete, fmtIntPtr(s.MaxTemperature), s.NightComplete)
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func equalIntPtr(a *int, b *int) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func fmtIntPtr(v *int) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
@@ -0,0 +1,149 @@
+package datapoint
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SeriesKind describes how the values of a TimeSeries are interpolated
+type SeriesKind int
+
+const (
+	// SeriesNumeric values are interpolated linearly
+	SeriesNumeric SeriesKind = iota
+	// SeriesDirection values are compass points interpolated around the shortest arc between them
+	SeriesDirection
+	// SeriesCategorical values, such as the weather type or visibility, take the value of the nearest point
+	SeriesCategorical
+)
+
+// SeriesPoint is the value of a parameter at a time
+type SeriesPoint struct {
+	Time time.Time
+	// Value is the numeric value of the parameter. For SeriesDirection this is the bearing in degrees, and for
+	// SeriesCategorical it is the parsed value if the category is a number, such as the weather type, otherwise 0
+	Value float64
+	// Text is the value as DataPoint would return it, such as '12', 'NNE' or 'VG'
+	Text string
+}
+
+// TimeSeries is the value of a single parameter over time, in time order
+type TimeSeries struct {
+	Parameter ParameterDescriptor
+	Kind      SeriesKind
+	Points    []SeriesPoint
+}
+
+// TimeSeries flattens the forecasts of every period into a time ordered series for the parameter, using the time of
+// each forecast. The second value is false if no forecast has the parameter
+func (s SiteRep) TimeSeries(param string) (TimeSeries, bool) {
+	series := TimeSeries{Kind: SeriesNumeric}
+	found := false
+	for _, period := range s.Location.Period {
+		for _, f := range period.Forecasts {
+			point := SeriesPoint{Time: f.Time}
+			if v, ok := f.IntParams[param]; ok {
+				series.Parameter = v.ParameterDescriptor
+				point.Value, point.Text = float64(v.Value), strconv.Itoa(v.Value)
+				if param == string(KnownParameterWeatherType) {
+					series.Kind = SeriesCategorical
+				}
+			} else if v, ok := f.FloatParams[param]; ok {
+				series.Parameter = v.ParameterDescriptor
+				point.Value, point.Text = v.Value, strconv.FormatFloat(v.Value, 'f', -1, 64)
+			} else if v, ok := f.StringParams[param]; ok {
+				series.Parameter = v.ParameterDescriptor
+				point.Text = v.Value
+				if unit, err := v.Unit(); err == nil && unit == UnitCompass {
+					series.Kind = SeriesDirection
+					if c, err := ParseCompassPoint(v.Value); err == nil {
+						point.Value, _ = c.Degrees()
+					}
+				} else if n, err := strconv.ParseFloat(v.Value, 64); err == nil && series.Kind == SeriesNumeric {
+					// observations report numeric values as strings
+					point.Value = n
+				} else {
+					series.Kind = SeriesCategorical
+				}
+			} else {
+				continue
+			}
+			found = true
+			series.Points = append(series.Points, point)
+		}
+	}
+
+	sort.SliceStable(series.Points, func(i, j int) bool {
+		return series.Points[i].Time.Before(series.Points[j].Time)
+	})
+	return series, found
+}
+
+// At returns the value of the series at the time, interpolated between the points either side of it. The second value
+// is false if the time is outside of the series
+func (t TimeSeries) At(at time.Time) (SeriesPoint, bool) {
+	i := sort.Search(len(t.Points), func(i int) bool {
+		return !t.Points[i].Time.Before(at)
+	})
+	if i == len(t.Points) {
+		return SeriesPoint{}, false
+	}
+	if t.Points[i].Time.Equal(at) {
+		return t.Points[i], true
+	}
+	if i == 0 {
+		return SeriesPoint{}, false
+	}
+
+	a, b := t.Points[i-1], t.Points[i]
+	fraction := float64(at.Sub(a.Time)) / float64(b.Time.Sub(a.Time))
+	nearest := a
+	if fraction > 0.5 {
+		nearest = b
+	}
+	nearest.Time = at
+
+	switch t.Kind {
+	case SeriesNumeric:
+		value := a.Value + (b.Value-a.Value)*fraction
+		return SeriesPoint{Time: at, Value: value, Text: strconv.FormatFloat(value, 'f', -1, 64)}, true
+	case SeriesDirection:
+		ca, errA := ParseCompassPoint(a.Text)
+		cb, errB := ParseCompassPoint(b.Text)
+		if errA != nil || errB != nil || !ca.IsDirectional() || !cb.IsDirectional() {
+			// calm and variable winds have no bearing to interpolate between
+			return nearest, true
+		}
+		delta := math.Mod(b.Value-a.Value+540, 360) - 180
+		value := normaliseDegrees(a.Value + delta*fraction)
+		return SeriesPoint{Time: at, Value: value, Text: CompassPointFromDegrees(value).String()}, true
+	default:
+		return nearest, true
+	}
+}
+
+// Resample returns the series at regular steps from its first point to its last, such as time.Hour to turn a three
+// hourly forecast into an hourly one
+func (t TimeSeries) Resample(step time.Duration) TimeSeries {
+	if len(t.Points) == 0 {
+		return TimeSeries{Parameter: t.Parameter, Kind: t.Kind}
+	}
+	return t.ResampleBetween(t.Points[0].Time, t.Points[len(t.Points)-1].Time, step)
+}
+
+// ResampleBetween returns the series at regular steps from one time to another inclusive. Times outside of the series
+// are left out
+func (t TimeSeries) ResampleBetween(from time.Time, to time.Time, step time.Duration) TimeSeries {
+	result := TimeSeries{Parameter: t.Parameter, Kind: t.Kind}
+	if step <= 0 {
+		return result
+	}
+	for at := from; !at.After(to); at = at.Add(step) {
+		if p, ok := t.At(at); ok {
+			result.Points = append(result.Points, p)
+		}
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package datapoint_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	dp "github.com/vitineth/datapoint"
+	"github.com/vitineth/datapoint/datapointtest"
+)
+
+func TestResample(t *testing.T) {
+	server := datapointtest.NewServer()
+	defer server.Close()
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	rep, err := client.FiveDayForecast(dp.ResolutionThreeHourly, datapointtest.DefaultForecastSites()[0].Id, nil)
+	if err != nil {
+		t.Fatalf("failed to fetch forecast: %v", err)
+	}
+
+	for name, kind := range map[string]dp.SeriesKind{"T": dp.SeriesNumeric, "D": dp.SeriesDirection, "W": dp.SeriesCategorical} {
+		series, ok := rep.TimeSeries(name)
+		if !ok {
+			t.Fatalf("expected a series for %v", name)
+		}
+		if series.Kind != kind {
+			t.Errorf("expected %v to be of kind %v but got %v", name, kind, series.Kind)
+		}
+
+		hourly := series.Resample(time.Hour)
+		if len(hourly.Points) != 3*(len(series.Points)-1)+1 {
+			t.Fatalf("expected %v hourly points for %v three hourly points of %v but got %v", 3*(len(series.Points)-1)+1, len(series.Points), name, len(hourly.Points))
+		}
+		for i, p := range hourly.Points {
+			a := series.Points[i/3]
+			if expected := a.Time.Add(time.Duration(i%3) * time.Hour); !p.Time.Equal(expected) {
+				t.Errorf("expected point %v of %v to be at %v but got %v", i, name, expected, p.Time)
+			}
+			if i%3 == 0 {
+				if p != a {
+					t.Errorf("expected point %v of %v to match the forecast %+v but got %+v", i, name, a, p)
+				}
+				continue
+			}
+
+			b := series.Points[i/3+1]
+			fraction := float64(i%3) / 3
+			switch kind {
+			case dp.SeriesNumeric:
+				if expected := a.Value + (b.Value-a.Value)*fraction; math.Abs(p.Value-expected) > 1e-9 {
+					t.Errorf("expected point %v of %v to be %v but got %v", i, name, expected, p.Value)
+				}
+			case dp.SeriesDirection:
+				if _, err := dp.ParseCompassPoint(p.Text); err != nil || p.Value < 0 || p.Value >= 360 {
+					t.Errorf("expected point %v of %v to be a bearing but got %+v", i, name, p)
+				}
+			case dp.SeriesCategorical:
+				nearest := a
+				if fraction > 0.5 {
+					nearest = b
+				}
+				if p.Text != nearest.Text {
+					t.Errorf("expected point %v of %v to take the nearest value %v but got %v", i, name, nearest.Text, p.Text)
+				}
+			}
+		}
+	}
+}
+
+func TestResampleDirection(t *testing.T) {
+	start := time.Date(2024, time.March, 14, 15, 0, 0, 0, time.UTC)
+	series := func(points ...dp.SeriesPoint) dp.TimeSeries {
+		for i := range points {
+			points[i].Time = start.Add(time.Duration(4*i) * time.Hour)
+		}
+		return dp.TimeSeries{Kind: dp.SeriesDirection, Points: points}
+	}
+
+	tests := []struct {
+		name     string
+		series   dp.TimeSeries
+		expected []float64
+		text     []string
+	}{
+		// the shortest arc between 350° and 10° passes through north rather than south
+		{"across north", series(dp.SeriesPoint{Value: 350, Text: "N"}, dp.SeriesPoint{Value: 10, Text: "N"}),
+			[]float64{350, 355, 0, 5, 10}, []string{"N", "N", "N", "N", "N"}},
+		{"across north backwards", series(dp.SeriesPoint{Value: 10, Text: "N"}, dp.SeriesPoint{Value: 350, Text: "N"}),
+			[]float64{10, 5, 0, 355, 350}, []string{"N", "N", "N", "N", "N"}},
+		{"without wrapping", series(dp.SeriesPoint{Value: 90, Text: "E"}, dp.SeriesPoint{Value: 180, Text: "S"}),
+			[]float64{90, 112.5, 135, 157.5, 180}, []string{"E", "ESE", "SE", "SSE", "S"}},
+		// calm winds have no bearing, so the nearest point is used
+		{"calm", series(dp.SeriesPoint{Value: 0, Text: "C"}, dp.SeriesPoint{Value: 90, Text: "E"}),
+			[]float64{0, 0, 0, 90, 90}, []string{"C", "C", "C", "E", "E"}},
+	}
+	for _, test := range tests {
+		hourly := test.series.Resample(time.Hour)
+		if len(hourly.Points) != len(test.expected) {
+			t.Errorf("%v: expected %v points but got %v", test.name, len(test.expected), len(hourly.Points))
+			continue
+		}
+		for i, p := range hourly.Points {
+			if math.Abs(p.Value-test.expected[i]) > 1e-9 || p.Text != test.text[i] {
+				t.Errorf("%v: expected point %v to be %v (%v) but got %v (%v)", test.name, i, test.expected[i], test.text[i], p.Value, p.Text)
+			}
+		}
+	}
+}
@@ -13,6 +13,9 @@ type ExtremeCapabilities struct {
 	ExtremeDate time.Time
 	// IssuedAt [official] is the date at which the observation was issued
 	IssuedAt time.Time
+	// Warnings [unofficial] contains any differences between the response and the expected schema, only populated in
+	// DecodingModeLenient
+	Warnings []Warning
 }
 
 type extremeCapabilitiesResponse struct {
@@ -35,6 +38,13 @@ func (d *DataPointClient) UkExtremesCapabilities() (*ExtremeCapabilities, error)
 		return nil, fmt.Errorf("failed to deserialise body from %v for capabilities: %w", target, err)
 	}
 
+	dec := d.newDecoder()
+	dec.checkSchema(body, &result)
+	warnings, err := dec.result(target)
+	if err != nil {
+		return nil, err
+	}
+
 	extremeDate, err := time.Parse(time.DateOnly, result.UkExtremes.ExtremeDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse date %v for extreme date: %w", result.UkExtremes.ExtremeDate, err)
@@ -47,6 +57,7 @@ func (d *DataPointClient) UkExtremesCapabilities() (*ExtremeCapabilities, error)
 	return &ExtremeCapabilities{
 		ExtremeDate: extremeDate,
 		IssuedAt:    issuedAt,
+		Warnings:    warnings,
 	}, nil
 }
 
@@ -59,7 +70,7 @@ type Extreme struct {
 	LocationName string
 	// Type [official] is the type of the extreme. For example 'HMAXT' would represent the highest maximum
 	//temperature, and 'LMINT' would represent the lowest minimum temperature.
-	Type string
+	Type ExtremeType
 	// UnitOfMeasurement [official - uom] is the unit of measurement for the extreme
 	UnitOfMeasurement string
 	// Value [official - $] is the value fo the observed extreme, in units specified in UnitOfMeasurement
@@ -84,6 +95,9 @@ type LatestExtremes struct {
 	IssuedAt time.Time
 	// Regions [official] are the regions in which observations are found
 	Regions []Region
+	// Warnings [unofficial] contains any differences between the response and the expected schema, only populated in
+	// DecodingModeLenient
+	Warnings []Warning
 }
 
 type latestExtremesResponse struct {
@@ -91,19 +105,19 @@ type latestExtremesResponse struct {
 		ExtremeDate string `json:"extremeDate"`
 		IssuedAt    string `json:"issuedAt"`
 		Regions     struct {
-			Region []struct {
+			Region oneOrMany[struct {
 				Id       string `json:"id"`
 				Name     string `json:"name"`
 				Extremes struct {
-					Extreme []struct {
+					Extreme oneOrMany[struct {
 						LocationId   string `json:"locId"`
 						LocationName string `json:"locationName"`
 						Type         string `json:"type"`
 						Uom          string `json:"uom"`
 						Value        string `json:"$"`
-					} `json:"Extreme"`
+					}] `json:"Extreme"`
 				} `json:"Extremes"`
-			} `json:"Region"`
+			}] `json:"Region"`
 		} `json:"Regions"`
 	} `json:"UkExtremes"`
 }
@@ -122,6 +136,13 @@ func (d *DataPointClient) UkExtremesLatest() (*LatestExtremes, error) {
 		return nil, fmt.Errorf("failed to deserialise body from %v for uk extremes latest: %w", target, err)
 	}
 
+	dec := d.newDecoder()
+	dec.checkSchema(body, &result)
+	warnings, err := dec.result(target)
+	if err != nil {
+		return nil, err
+	}
+
 	regions := make([]Region, len(result.UkExtremes.Regions.Region))
 	for i, region := range result.UkExtremes.Regions.Region {
 		extremes := make([]Extreme, len(region.Extremes.Extreme))
@@ -139,7 +160,7 @@ func (d *DataPointClient) UkExtremesLatest() (*LatestExtremes, error) {
 			extremes[j] = Extreme{
 				LocationId:        int(locationId),
 				LocationName:      extreme.LocationName,
-				Type:              extreme.Type,
+				Type:              ExtremeType(extreme.Type),
 				UnitOfMeasurement: extreme.Uom,
 				Value:             value,
 			}
@@ -166,6 +187,7 @@ func (d *DataPointClient) UkExtremesLatest() (*LatestExtremes, error) {
 		ExtremeDate: extremeDate,
 		IssuedAt:    issuedAt,
 		Regions:     regions,
+		Warnings:    warnings,
 	}, nil
 }
 
@@ -175,14 +197,18 @@ type RegionalForecastSite struct {
 	Id int
 	// Name [official] The short name of the region
 	Name string
+	// Warnings [unofficial] contains any differences between the response and the expected schema for this region,
+	// only populated in DecodingModeLenient. Differences which apply to the whole list are attached to the first region
+	// only
+	Warnings []Warning
 }
 
 type regionalForecastSiteListResponse struct {
 	Locations struct {
-		Location []struct {
+		Location oneOrMany[struct {
 			Id   string `json:"@id"`
 			Name string `json:"@name"`
-		} `json:"Location"`
+		}] `json:"Location"`
 	} `json:"Locations"`
 }
 
@@ -201,6 +227,9 @@ func (d *DataPointClient) RegionalForecastSiteList() ([]RegionalForecastSite, er
 		return nil, fmt.Errorf("failed to deserialise body from %v for regional forecast site list: %w", target, err)
 	}
 
+	dec := d.newDecoder()
+	dec.checkSchema(body, &result)
+
 	locations := make([]RegionalForecastSite, len(result.Locations.Location))
 	for i, s := range result.Locations.Location {
 		id, err := strconv.ParseInt(s.Id, 10, 64)
@@ -214,12 +243,23 @@ func (d *DataPointClient) RegionalForecastSiteList() ([]RegionalForecastSite, er
 		}
 	}
 
+	_, err = dec.result(target)
+	if err != nil {
+		return nil, err
+	}
+	for i, warnings := range dec.split("Locations.Location", len(locations)) {
+		locations[i].Warnings = warnings
+	}
+
 	return locations, nil
 }
 
 // RegionalForecastCapabilities indicates when the last set of regional forecasts were issues by the Met Office
 type RegionalForecastCapabilities struct {
 	IssuedAt time.Time
+	// Warnings [unofficial] contains any differences between the response and the expected schema, only populated in
+	// DecodingModeLenient
+	Warnings []Warning
 }
 
 type regionalForecastCapabilitiesResponse struct {
@@ -242,12 +282,19 @@ func (d *DataPointClient) RegionalForecastCapabilities() (*RegionalForecastCapab
 		return nil, fmt.Errorf("failed to deserialise body from %v for regional forecast capabilities: %w", target, err)
 	}
 
-	issued, err := time.Parse(result.RegionalForecast.IssuedAt, time.RFC3339)
+	dec := d.newDecoder()
+	dec.checkSchema(body, &result)
+	warnings, err := dec.result(target)
+	if err != nil {
+		return nil, err
+	}
+
+	issued, err := time.Parse(time.RFC3339, result.RegionalForecast.IssuedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse issued at time: %w", err)
 	}
 
-	return &RegionalForecastCapabilities{IssuedAt: issued}, nil
+	return &RegionalForecastCapabilities{IssuedAt: issued, Warnings: warnings}, nil
 }
 
 //
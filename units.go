@@ -0,0 +1,241 @@
+package datapoint
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Unit is a unit of measurement as it appears in ParameterDescriptor.Units, such as 'C' or 'mph'
+type Unit string
+
+const (
+	UnitNone    Unit = ""
+	UnitPercent Unit = "%"
+	UnitCompass Unit = "compass"
+
+	UnitCelsius    Unit = "C"
+	UnitFahrenheit Unit = "F"
+	UnitKelvin     Unit = "K"
+
+	UnitMilesPerHour      Unit = "mph"
+	UnitKilometresPerHour Unit = "km/h"
+	UnitMetresPerSecond   Unit = "m/s"
+	UnitKnots             Unit = "kn"
+	UnitBeaufort          Unit = "Bft"
+
+	UnitMillimetres   Unit = "mm"
+	UnitInches        Unit = "in"
+	UnitMetres        Unit = "m"
+	UnitKilometres    Unit = "km"
+	UnitMiles         Unit = "mi"
+	UnitFeet          Unit = "ft"
+	UnitNauticalMiles Unit = "nmi"
+
+	UnitHectopascals    Unit = "hPa"
+	UnitMillibars       Unit = "mb"
+	UnitPascals         Unit = "Pa"
+	UnitKilopascals     Unit = "kPa"
+	UnitInchesOfMercury Unit = "inHg"
+	UnitMillimetresOfHg Unit = "mmHg"
+
+	UnitSeconds Unit = "s"
+	UnitMinutes Unit = "min"
+	UnitHours   Unit = "h"
+)
+
+// Quantity is the kind of measure a Unit describes. Only units of the same quantity can be converted between
+type Quantity int
+
+const (
+	QuantityNone Quantity = iota
+	QuantityRatio
+	QuantityDirection
+	QuantityTemperature
+	QuantitySpeed
+	QuantityDistance
+	QuantityPressure
+	QuantityDuration
+)
+
+type unitInfo struct {
+	quantity Quantity
+	// scale converts a value in this unit into the base unit of its quantity (K, m/s, m, Pa or s). Temperatures also use
+	// offset, and the Beaufort scale is handled separately as it is not linear
+	scale  float64
+	offset float64
+}
+
+var units = map[Unit]unitInfo{
+	UnitNone:    {QuantityNone, 1, 0},
+	UnitPercent: {QuantityRatio, 1, 0},
+	UnitCompass: {QuantityDirection, 1, 0},
+
+	UnitCelsius:    {QuantityTemperature, 1, 273.15},
+	UnitFahrenheit: {QuantityTemperature, 5.0 / 9.0, 273.15 - 32*5.0/9.0},
+	UnitKelvin:     {QuantityTemperature, 1, 0},
+
+	UnitMilesPerHour:      {QuantitySpeed, 0.44704, 0},
+	UnitKilometresPerHour: {QuantitySpeed, 1 / 3.6, 0},
+	UnitMetresPerSecond:   {QuantitySpeed, 1, 0},
+	UnitKnots:             {QuantitySpeed, 1852.0 / 3600.0, 0},
+	UnitBeaufort:          {QuantitySpeed, 0, 0},
+
+	UnitMillimetres:   {QuantityDistance, 0.001, 0},
+	UnitInches:        {QuantityDistance, 0.0254, 0},
+	UnitMetres:        {QuantityDistance, 1, 0},
+	UnitKilometres:    {QuantityDistance, 1000, 0},
+	UnitMiles:         {QuantityDistance, 1609.344, 0},
+	UnitFeet:          {QuantityDistance, 0.3048, 0},
+	UnitNauticalMiles: {QuantityDistance, 1852, 0},
+
+	UnitHectopascals:    {QuantityPressure, 100, 0},
+	UnitMillibars:       {QuantityPressure, 100, 0},
+	UnitPascals:         {QuantityPressure, 1, 0},
+	UnitKilopascals:     {QuantityPressure, 1000, 0},
+	UnitInchesOfMercury: {QuantityPressure, 3386.389, 0},
+	UnitMillimetresOfHg: {QuantityPressure, 133.322387415, 0},
+
+	UnitSeconds: {QuantityDuration, 1, 0},
+	UnitMinutes: {QuantityDuration, 60, 0},
+	UnitHours:   {QuantityDuration, 3600, 0},
+}
+
+// unitAliases maps the lower case spellings seen in DataPoint feeds and elsewhere onto a Unit
+var unitAliases = map[string]Unit{
+	"":        UnitNone,
+	"%":       UnitPercent,
+	"percent": UnitPercent,
+	"compass": UnitCompass,
+
+	"c":          UnitCelsius,
+	"degc":       UnitCelsius,
+	"°c":         UnitCelsius,
+	"celsius":    UnitCelsius,
+	"f":          UnitFahrenheit,
+	"degf":       UnitFahrenheit,
+	"°f":         UnitFahrenheit,
+	"fahrenheit": UnitFahrenheit,
+	"k":          UnitKelvin,
+	"kelvin":     UnitKelvin,
+
+	"mph":      UnitMilesPerHour,
+	"km/h":     UnitKilometresPerHour,
+	"kph":      UnitKilometresPerHour,
+	"kmh":      UnitKilometresPerHour,
+	"m/s":      UnitMetresPerSecond,
+	"ms-1":     UnitMetresPerSecond,
+	"mps":      UnitMetresPerSecond,
+	"kn":       UnitKnots,
+	"kt":       UnitKnots,
+	"kts":      UnitKnots,
+	"knots":    UnitKnots,
+	"bft":      UnitBeaufort,
+	"beaufort": UnitBeaufort,
+
+	"mm":     UnitMillimetres,
+	"in":     UnitInches,
+	"inches": UnitInches,
+	"m":      UnitMetres,
+	"metres": UnitMetres,
+	"meters": UnitMetres,
+	"km":     UnitKilometres,
+	"mi":     UnitMiles,
+	"miles":  UnitMiles,
+	"ft":     UnitFeet,
+	"feet":   UnitFeet,
+	"nmi":    UnitNauticalMiles,
+
+	"hpa":  UnitHectopascals,
+	"mb":   UnitMillibars,
+	"mbar": UnitMillibars,
+	"pa":   UnitPascals,
+	"kpa":  UnitKilopascals,
+	"inhg": UnitInchesOfMercury,
+	"mmhg": UnitMillimetresOfHg,
+
+	"s":       UnitSeconds,
+	"seconds": UnitSeconds,
+	"min":     UnitMinutes,
+	"minutes": UnitMinutes,
+	"h":       UnitHours,
+	"hr":      UnitHours,
+	"hrs":     UnitHours,
+	"hours":   UnitHours,
+}
+
+// beaufortLimits holds the upper bound in m/s of each force on the Beaufort scale below 12
+var beaufortLimits = []float64{0.5, 1.6, 3.4, 5.5, 8.0, 10.8, 13.9, 17.2, 20.8, 24.5, 28.5, 32.7}
+
+// ParseUnit converts a unit string such as 'C', 'degC', 'mph' or 'km/h' into a Unit. Parsing is not case-sensitive
+func ParseUnit(value string) (Unit, error) {
+	u, ok := unitAliases[strings.ToLower(strings.TrimSpace(value))]
+	if !ok {
+		return UnitNone, fmt.Errorf("unknown unit %v", value)
+	}
+	return u, nil
+}
+
+// Quantity returns the kind of measure the unit describes
+func (u Unit) Quantity() Quantity {
+	return units[u].quantity
+}
+
+// Convert converts a value from one unit into another of the same quantity. Converting into UnitBeaufort returns the
+// whole force on the scale, while converting out of it uses the empirical relationship v = 0.836 B^1.5 m/s
+func Convert(value float64, from Unit, to Unit) (float64, error) {
+	f, ok := units[from]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %v", from)
+	}
+	t, ok := units[to]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %v", to)
+	}
+	if f.quantity != t.quantity {
+		return 0, fmt.Errorf("cannot convert from %v to %v", from, to)
+	}
+	if from == to {
+		return value, nil
+	}
+
+	var base float64
+	if from == UnitBeaufort {
+		base = 0.836 * math.Pow(math.Max(value, 0), 1.5)
+	} else {
+		base = value*f.scale + f.offset
+	}
+
+	if to == UnitBeaufort {
+		for force, limit := range beaufortLimits {
+			if base < limit {
+				return float64(force), nil
+			}
+		}
+		return float64(len(beaufortLimits)), nil
+	}
+	return (base - t.offset) / t.scale, nil
+}
+
+// Unit returns the parsed units of the parameter
+func (p ParameterDescriptor) Unit() (Unit, error) {
+	return ParseUnit(p.Units)
+}
+
+// Convert returns the value of the parameter in a different unit
+func (p IntParameterValue) Convert(to Unit) (float64, error) {
+	from, err := p.Unit()
+	if err != nil {
+		return 0, err
+	}
+	return Convert(float64(p.Value), from, to)
+}
+
+// Convert returns the value of the parameter in a different unit
+func (p FloatParameterValue) Convert(to Unit) (float64, error) {
+	from, err := p.Unit()
+	if err != nil {
+		return 0, err
+	}
+	return Convert(p.Value, from, to)
+}
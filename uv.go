@@ -0,0 +1,145 @@
+package datapoint
+
+import "time"
+
+// UvCategory is one of the World Health Organisation exposure categories for the UV index
+type UvCategory int
+
+const (
+	UvCategoryLow UvCategory = iota
+	UvCategoryModerate
+	UvCategoryHigh
+	UvCategoryVeryHigh
+	UvCategoryExtreme
+)
+
+type uvCategoryInfo struct {
+	key    string
+	colour string
+}
+
+var uvCategories = map[UvCategory]uvCategoryInfo{
+	UvCategoryLow:      {"low", "#289500"},
+	UvCategoryModerate: {"moderate", "#F7E400"},
+	UvCategoryHigh:     {"high", "#F85900"},
+	UvCategoryVeryHigh: {"very-high", "#D8001D"},
+	UvCategoryExtreme:  {"extreme", "#6B49C8"},
+}
+
+// UvProtectionThreshold is the lowest UV index at which the WHO recommends sun protection
+const UvProtectionThreshold UvIndex = 3
+
+// Category returns the exposure category of the index. An index of 0 is in UvCategoryLow, as is any negative value
+func (i UvIndex) Category() UvCategory {
+	switch {
+	case i.IsExtremeExposure():
+		return UvCategoryExtreme
+	case i.IsVeryHighExposure():
+		return UvCategoryVeryHigh
+	case i.IsHighExposure():
+		return UvCategoryHigh
+	case i.IsModerateExposure():
+		return UvCategoryModerate
+	default:
+		return UvCategoryLow
+	}
+}
+
+// RequiresProtection returns if the WHO recommends sun protection at this index
+func (i UvIndex) RequiresProtection() bool {
+	return i >= UvProtectionThreshold
+}
+
+// String returns the English name of the category, e.g. 'Very high'
+func (c UvCategory) String() string {
+	return c.Localise(LanguageEnglish)
+}
+
+// Colour returns the WHO recommended colour for the category as a hex string, e.g. '#F85900'
+func (c UvCategory) Colour() string {
+	return uvCategories[c].colour
+}
+
+// Advice returns the WHO recommended protective action for the category in English
+func (c UvCategory) Advice() string {
+	return c.LocaliseAdvice(LanguageEnglish)
+}
+
+// Localise returns the name of the category in the language
+func (c UvCategory) Localise(language Language) string {
+	return translateOr(language, "uv."+uvCategories[c].key, "")
+}
+
+// LocaliseAdvice returns the recommended protective action for the category in the language
+func (c UvCategory) LocaliseAdvice(language Language) string {
+	return translateOr(language, "uv.advice."+uvCategories[c].key, "")
+}
+
+// UvWindow is a continuous period of time covered by forecasts with a UV index at or above a level
+type UvWindow struct {
+	// From is the start of the first forecast in the window
+	From time.Time
+	// To is the end of the last forecast in the window
+	To time.Time
+	// Peak is the highest UV index forecast during the window
+	Peak UvIndex
+}
+
+// Category returns the exposure category of the peak of the window
+func (w UvWindow) Category() UvCategory {
+	return w.Peak.Category()
+}
+
+// UvWindows returns every continuous period during which the forecast UV index is at or above the threshold, in time
+// order. Forecasts are continuous when one is valid until the time the next becomes valid, so the day segments of a
+// daily forecast are always separate windows
+func (s SiteRep) UvWindows(threshold UvIndex) []UvWindow {
+	var windows []UvWindow
+	var current *UvWindow
+	for _, period := range s.Location.Period {
+		for _, f := range period.Forecasts {
+			uv, ok := f.UvIndex()
+			if !ok || uv < threshold {
+				current = nil
+				continue
+			}
+
+			if current != nil && !f.ValidFrom.After(current.To) {
+				current.To = f.ValidTo
+				current.Peak = max(current.Peak, uv)
+				continue
+			}
+
+			windows = append(windows, UvWindow{From: f.ValidFrom, To: f.ValidTo, Peak: uv})
+			current = &windows[len(windows)-1]
+		}
+	}
+	return windows
+}
+
+// ProtectionWindows returns every period during which the WHO recommends sun protection
+func (s SiteRep) ProtectionWindows() []UvWindow {
+	return s.UvWindows(UvProtectionThreshold)
+}
+
+// PeakUvWindow returns the earliest continuous period during which the UV index is at its highest across the whole
+// forecast. The second value is false if the forecast contains no UV index
+func (s SiteRep) PeakUvWindow() (UvWindow, bool) {
+	peak := UvIndex(-1)
+	for _, period := range s.Location.Period {
+		for _, f := range period.Forecasts {
+			if uv, ok := f.UvIndex(); ok {
+				peak = max(peak, uv)
+			}
+		}
+	}
+	if peak < 0 {
+		return UvWindow{}, false
+	}
+
+	windows := s.UvWindows(peak)
+	if len(windows) == 0 {
+		return UvWindow{}, false
+	}
+	return windows[0], true
+}
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log/slog"
+	"reflect"
 	"slices"
 	"strconv"
 	"time"
@@ -12,15 +12,17 @@ import (
 
 type siteResponse struct {
 	Locations struct {
-		Location []struct {
-			Elevation       string `json:"elevation"`
+		Location oneOrMany[struct {
+			Elevation       string `json:"elevation" dp:"optional"`
 			Id              string `json:"id"`
 			Latitude        string `json:"latitude"`
 			Longitude       string `json:"longitude"`
 			Name            string `json:"name"`
-			Region          string `json:"region"`
-			UnitaryAuthArea string `json:"unitaryAuthArea"`
-		} `json:"Location"`
+			Region          string `json:"region" dp:"optional"`
+			UnitaryAuthArea string `json:"unitaryAuthArea" dp:"optional"`
+			// ObsSource is only returned for observation sites and is not currently exposed
+			ObsSource string `json:"obsSource" dp:"optional"`
+		}] `json:"Location"`
 	} `json:"Locations"`
 }
 
@@ -40,13 +42,23 @@ type Site struct {
 	Region string `json:"region"`
 	// UnitaryAuthArea [undocumented]: The unitary auth area of the location
 	UnitaryAuthArea string `json:"unitaryAuthArea"`
+	// Warnings [unofficial] contains any values for this site which could not be interpreted and, in DecodingModeLenient,
+	// any differences between the response and the expected schema. Differences which apply to the whole list are
+	// attached to the first site only
+	Warnings []Warning `json:"-"`
 }
 
 // ForecastSiteList returns the 5,000 UK locations forecast site list data feed provides a list of the locations (also known as sites) for which
 // results are available for the 5,000 UK locations three hourly forecast and 5,000 UK locations daily forecast data
 // feeds. You can use this data feed to find details such as the ID of the site that you are interested in finding data
 // for.
+//
+// If the client was created with WithSiteListSnapshot the snapshot is returned until the live list has been fetched
+// in the background
 func (d *DataPointClient) ForecastSiteList() ([]Site, error) {
+	if cache, ok := d.siteLists["wxfcs"]; ok {
+		return d.cachedSiteList("wxfcs", cache)
+	}
 	return d.siteList("wxfcs")
 }
 
@@ -54,7 +66,13 @@ func (d *DataPointClient) ForecastSiteList() ([]Site, error) {
 // results are available for the hourly observations data feed.
 // You can use this to find the ID of the site that you are
 // interested in
+//
+// If the client was created with WithSiteListSnapshot the snapshot is returned until the live list has been fetched
+// in the background
 func (d *DataPointClient) ObservationSiteList() ([]Site, error) {
+	if cache, ok := d.siteLists["wxobs"]; ok {
+		return d.cachedSiteList("wxobs", cache)
+	}
 	return d.siteList("wxobs")
 }
 
@@ -70,6 +88,9 @@ func (d *DataPointClient) siteList(id string) ([]Site, error) {
 		return nil, fmt.Errorf("failed to deserialise body from %v for sitelist: %w", target, err)
 	}
 
+	dec := d.newDecoder()
+	dec.checkSchema(body, &result)
+
 	entries := make([]Site, len(result.Locations.Location))
 	for i, site := range result.Locations.Location {
 		latitude, err := strconv.ParseFloat(site.Latitude, 64)
@@ -88,7 +109,7 @@ func (d *DataPointClient) siteList(id string) ([]Site, error) {
 		if site.Elevation != "" {
 			elev, err := strconv.ParseFloat(site.Elevation, 64)
 			if err != nil {
-				slog.Warn("failed to parse elevation", "elevation", site.Elevation, "err", err)
+				dec.warn(fmt.Sprintf("Locations.Location[%d].elevation", i), "failed to parse elevation %v: %v", site.Elevation, err)
 			} else {
 				elevation = elev
 			}
@@ -105,6 +126,14 @@ func (d *DataPointClient) siteList(id string) ([]Site, error) {
 		}
 	}
 
+	_, err = dec.result(target)
+	if err != nil {
+		return nil, err
+	}
+	for i, warnings := range dec.split("Locations.Location", len(entries)) {
+		entries[i].Warnings = warnings
+	}
+
 	return entries, nil
 }
 
@@ -114,7 +143,7 @@ type capabilitiesResponse struct {
 		Resolution Resolution `json:"res"`
 		Type       string     `json:"type"`
 		TimeSteps  struct {
-			TS []string `json:"TS"`
+			TS oneOrMany[string] `json:"TS"`
 		} `json:"TimeSteps"`
 	} `json:"Resource"`
 }
@@ -135,6 +164,9 @@ type TimeSteps struct {
 	// provides a description of a single available timestep, expressed according to the ISO 8601
 	// combined date and time convention. e.g. '2012-11-21T06:00:00Z
 	TimeSteps []time.Time
+	// Warnings [unofficial] contains any differences between the response and the expected schema, only populated in
+	// DecodingModeLenient
+	Warnings []Warning
 }
 
 // ForecastTimeStepCapabilities exposes the capabilities data feed which provides a summary of the timesteps for which results are available for the 5,000 UK
@@ -142,7 +174,7 @@ type TimeSteps struct {
 // interested in is available before querying the relevant web service to get the data. In this way you can minimise the
 // number of redundant calls that have to be made.
 func (d *DataPointClient) ForecastTimeStepCapabilities(resolution Resolution) (*TimeSteps, error) {
-	body, _, err := d.fetch(
+	body, target, err := d.fetch(
 		"capabilities",
 		"val/wxfcs/all/json/capabilities",
 		map[string]string{
@@ -159,6 +191,13 @@ func (d *DataPointClient) ForecastTimeStepCapabilities(resolution Resolution) (*
 		return nil, fmt.Errorf("failed to deserialise body for capabilities: %w", err)
 	}
 
+	dec := d.newDecoder()
+	dec.checkSchema(body, &ts)
+	warnings, err := dec.result(target)
+	if err != nil {
+		return nil, err
+	}
+
 	dataDate, err := time.Parse(time.RFC3339, ts.Resource.DataDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse time step for capabilities: %w", err)
@@ -178,15 +217,16 @@ func (d *DataPointClient) ForecastTimeStepCapabilities(resolution Resolution) (*
 		Resolution: ts.Resource.Resolution,
 		Type:       ts.Resource.Type,
 		TimeSteps:  times,
+		Warnings:   warnings,
 	}, nil
 }
 
 type wxResponse struct {
-	Param []struct {
+	Param oneOrMany[struct {
 		Name        string `json:"name"`
 		Units       string `json:"units"`
 		Description string `json:"$"`
-	} `json:"Param"`
+	}] `json:"Param"`
 }
 
 type locationEntry struct {
@@ -196,24 +236,24 @@ type locationEntry struct {
 	Name      string `json:"name"`
 	Country   string `json:"country"`
 	Continent string `json:"continent"`
-	Elevation string `json:"elevation"`
-	Period    []struct {
-		Type  string              `json:"type"`
-		Value string              `json:"value"`
-		Rep   []map[string]string `json:"Rep"`
-	} `json:"Period"`
+	Elevation string `json:"elevation" dp:"optional"`
+	Period    oneOrMany[struct {
+		Type  string                       `json:"type"`
+		Value string                       `json:"value"`
+		Rep   oneOrMany[map[string]string] `json:"Rep"`
+	}] `json:"Period"`
 }
 
 type dvEntry struct {
 	DataDate string        `json:"dataDate"`
 	Type     string        `json:"type"`
-	Location locationEntry `json:"Location"`
+	Location locationEntry `json:"Location" dp:"optional"`
 }
 
 type dvMultipleEntry struct {
-	DataDate string          `json:"dataDate"`
-	Type     string          `json:"type"`
-	Location []locationEntry `json:"Location"`
+	DataDate string                   `json:"dataDate"`
+	Type     string                   `json:"type"`
+	Location oneOrMany[locationEntry] `json:"Location"`
 }
 
 type siteRepResponse struct {
@@ -253,13 +293,32 @@ type IntParameterValue struct {
 	Value int
 }
 
+// FloatParameterValue is a combination of a decimal value and a parameter definition
+type FloatParameterValue struct {
+	ParameterDescriptor
+	// Value [official] the value of the measure
+	Value float64
+}
+
 // Forecast represents a single forecast issues for a specific time, which contains a set of parameters describing the weather at that time
 type Forecast struct {
-	// Time [unofficial] is the time at which this forecast represents, this is derived from the time offset returned in the response
+	// Time [unofficial] is the time at which this forecast represents, this is derived from the time offset returned in the response.
+	// For daily forecasts this is the start of the day or night segment
 	Time time.Time
-	// IntParams contains all known parameters which will always be numeric, anything else will appear in StringParams
+	// Segment [official - $] is the part of the day covered by a daily forecast, or SegmentNone for other resolutions
+	Segment Segment
+	// ValidFrom [unofficial] is the start of the period this forecast covers. For three hourly forecasts this is the
+	// same as Time
+	ValidFrom time.Time
+	// ValidTo [unofficial] is the end of the period this forecast covers. For three hourly forecasts this is the time
+	// of the next time step
+	ValidTo time.Time
+	// IntParams contains all registered parameters which will always be integers
 	IntParams map[string]IntParameterValue
-	// StringParams contains all parameters which are not known to be integers
+	// FloatParams contains all registered parameters which will always be decimals
+	FloatParams map[string]FloatParameterValue
+	// StringParams contains all enum and string parameters, along with any parameters which are not registered or
+	// could not be parsed
 	StringParams map[string]StringParameterValue
 }
 
@@ -299,32 +358,18 @@ type SiteRep struct {
 	DataDate time.Time
 	// Type [official] is the type of data that is returned (Forecast or Obs)
 	Type string
+	// Resolution [unofficial] is the resolution which was requested for this forecast
+	Resolution Resolution
 	// Location [official] is the combination of location information and forecast data
 	Location LocationRep
+	// Warnings [unofficial] contains any values for this location which could not be interpreted and, in
+	// DecodingModeLenient, any differences between the response and the expected schema. When returned from
+	// FiveDayForecastForAllLocations, differences which apply to the whole response are attached to the first location
+	// only
+	Warnings []Warning
 }
 
-var (
-	knownIntParams = []string{
-		string(KnownParameterFeelsLikeTemp),
-		string(KnownParameterWindGust),
-		string(KnownParameterScreenRelativeHumidity),
-		string(KnownParameterPrecipitationProbability),
-		string(KnownParameterWindSpeed),
-		string(KnownParameterTemperature),
-		string(KnownParameterWeatherType),
-		string(KnownParameterMaxUvIndex),
-		string(KnownParameterWindGustNoon),
-		string(KnownParameterScreenRelativeHumidityNoon),
-		string(KnownParameterDayMaximumTemperature),
-		string(KnownParameterNightMinimumTemperature),
-		string(KnownParameterFeelsLikeDayMaximumTemperature),
-		string(KnownParameterPrecipitationProbabilityDay),
-		string(KnownParameterPrecipitationProbabilityNight),
-		"$",
-	}
-)
-
-func convertLocation(paramDefinitions map[string]ParameterDescriptor, typeName string, startTime time.Time, entry locationEntry) (*SiteRep, error) {
+func convertLocation(dec *decoder, path string, registry *ParameterRegistry, paramDefinitions map[string]ParameterDescriptor, typeName string, resolution Resolution, startTime time.Time, entry locationEntry) (*SiteRep, error) {
 	periods := make([]Period, len(entry.Period))
 	for i, entry := range entry.Period {
 		periodTime, err := time.Parse("2006-01-02Z", entry.Value)
@@ -335,6 +380,7 @@ func convertLocation(paramDefinitions map[string]ParameterDescriptor, typeName s
 		forecasts := make([]Forecast, len(entry.Rep))
 		for forecastIndex, rep := range entry.Rep {
 			intParams := map[string]IntParameterValue{}
+			floatParams := map[string]FloatParameterValue{}
 			stringParams := map[string]StringParameterValue{}
 
 			offsetString, ok := rep["$"]
@@ -342,17 +388,18 @@ func convertLocation(paramDefinitions map[string]ParameterDescriptor, typeName s
 				return nil, errors.New("could not find forecast offset")
 			}
 
-			var offset int
-			if offsetString == "Day" {
-				offset = 0
-			} else if offsetString == "Night" {
-				offset = 86399 // 23 hours, 59 minutes, 59 seconds
+			segment := SegmentNone
+			var validFrom, validTo time.Time
+			if offsetString == string(SegmentDay) || offsetString == string(SegmentNight) {
+				segment = Segment(offsetString)
+				validFrom, validTo = segment.Window(periodTime)
 			} else {
-				offset64, err := strconv.ParseInt(offsetString, 10, 16)
+				offset, err := strconv.ParseInt(offsetString, 10, 16)
 				if err != nil {
 					return nil, fmt.Errorf("failed to parse forecast offset: %w", err)
 				}
-				offset = int(offset64) * 60
+				validFrom = periodTime.Add(time.Duration(offset) * time.Minute)
+				validTo = validFrom.Add(resolution.step())
 			}
 
 			for k, v := range rep {
@@ -360,31 +407,73 @@ func convertLocation(paramDefinitions map[string]ParameterDescriptor, typeName s
 					continue
 				}
 
-				descriptor, ok := paramDefinitions[k]
-				if !ok {
-					return nil, fmt.Errorf("could not find descriptor for parameter %v", k)
+				paramPath := fmt.Sprintf("%v.Period[%d].Rep[%d].%v", path, i, forecastIndex, k)
+				descriptor, described := paramDefinitions[k]
+				if !described {
+					dec.warn(paramPath, "could not find descriptor for parameter")
+					descriptor = ParameterDescriptor{Name: k}
 				}
 
-				if slices.Contains(knownIntParams, k) {
-					v, err := strconv.ParseInt(v, 10, 16)
-					if err != nil {
-						return nil, fmt.Errorf("failed to parse known int value %v: %w", k, err)
+				definition, ok := registry.Lookup(k)
+				if !ok {
+					stringParams[k] = StringParameterValue{
+						ParameterDescriptor: descriptor,
+						Value:               v,
 					}
+					continue
+				}
+
+				if described && descriptor.Units != definition.Units {
+					dec.warn(paramPath, "expected units %q but found %q", definition.Units, descriptor.Units)
+				}
+
+				parsed, err := definition.parse(v)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse known value %v of %v: %w", v, paramPath, err)
+				}
+				if err := definition.validate(parsed); err != nil {
+					dec.warn(paramPath, "invalid value: %v", err)
+				}
+
+				switch value := parsed.(type) {
+				case int:
 					intParams[k] = IntParameterValue{
 						ParameterDescriptor: descriptor,
-						Value:               int(v),
+						Value:               value,
 					}
-				} else {
+				case float64:
+					floatParams[k] = FloatParameterValue{
+						ParameterDescriptor: descriptor,
+						Value:               value,
+					}
+				case string:
 					stringParams[k] = StringParameterValue{
 						ParameterDescriptor: descriptor,
-						Value:               v,
+						Value:               value,
+					}
+				}
+			}
+
+			if dec.checking() {
+				var missing []string
+				for name := range paramDefinitions {
+					if _, ok := rep[name]; !ok && segment.includes(name) {
+						missing = append(missing, name)
 					}
 				}
+				slices.Sort(missing)
+				for _, name := range missing {
+					dec.warn(fmt.Sprintf("%v.Period[%d].Rep[%d].%v", path, i, forecastIndex, name), "missing parameter")
+				}
 			}
 
 			forecasts[forecastIndex] = Forecast{
-				Time:         periodTime.Add(time.Duration(offset) * time.Second),
+				Time:         validFrom,
+				Segment:      segment,
+				ValidFrom:    validFrom,
+				ValidTo:      validTo,
 				IntParams:    intParams,
+				FloatParams:  floatParams,
 				StringParams: stringParams,
 			}
 		}
@@ -420,8 +509,9 @@ func convertLocation(paramDefinitions map[string]ParameterDescriptor, typeName s
 	}
 
 	return &SiteRep{
-		DataDate: startTime,
-		Type:     typeName,
+		DataDate:   startTime,
+		Type:       typeName,
+		Resolution: resolution,
 		Location: LocationRep{
 			Id:        int(id),
 			Latitude:  lat,
@@ -464,6 +554,9 @@ func (d *DataPointClient) FiveDayForecast(resolution Resolution, locationID int,
 		return nil, nil
 	}
 
+	dec := d.newDecoder()
+	dec.checkSchema(body, &result)
+
 	paramDefinitions := map[string]ParameterDescriptor{}
 	for _, p := range result.SiteRep.Wx.Param {
 		paramDefinitions[p.Name] = ParameterDescriptor{
@@ -478,12 +571,26 @@ func (d *DataPointClient) FiveDayForecast(resolution Resolution, locationID int,
 		return nil, fmt.Errorf("failed to parse period start time: %w", err)
 	}
 
-	return convertLocation(
+	rep, err := convertLocation(
+		dec,
+		"SiteRep.DV.Location",
+		d.parameters,
 		paramDefinitions,
 		result.SiteRep.Dv.Type,
+		resolution,
 		startTime,
 		result.SiteRep.Dv.Location,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	rep.Warnings, err = dec.result(target)
+	if err != nil {
+		return nil, err
+	}
+
+	return rep, nil
 }
 
 // FiveDayForecastForAllLocations implements the same functionality as FiveDayForecast but returns the results for all
@@ -506,6 +613,9 @@ func (d *DataPointClient) FiveDayForecastForAllLocations(resolution Resolution,
 		return nil, fmt.Errorf("failed to deserialise body from %v for sitelist: %w", target, err)
 	}
 
+	dec := d.newDecoder()
+	dec.checkSchema(body, &result)
+
 	paramDefinitions := map[string]ParameterDescriptor{}
 	for _, p := range result.SiteRep.Wx.Param {
 		paramDefinitions[p.Name] = ParameterDescriptor{
@@ -523,8 +633,12 @@ func (d *DataPointClient) FiveDayForecastForAllLocations(resolution Resolution,
 	reps := make([]SiteRep, len(result.SiteRep.Dv.Location))
 	for i, entry := range result.SiteRep.Dv.Location {
 		r, err := convertLocation(
+			dec,
+			fmt.Sprintf("SiteRep.DV.Location[%d]", i),
+			d.parameters,
 			paramDefinitions,
 			result.SiteRep.Dv.Type,
+			resolution,
 			startTime,
 			entry,
 		)
@@ -535,5 +649,306 @@ func (d *DataPointClient) FiveDayForecastForAllLocations(resolution Resolution,
 		reps[i] = *r
 	}
 
+	_, err = dec.result(target)
+	if err != nil {
+		return nil, err
+	}
+	for i, warnings := range dec.split("SiteRep.DV.Location", len(reps)) {
+		reps[i].Warnings = warnings
+	}
+
 	return reps, nil
 }
+
+// FiveDayForecastForAllLocationsFunc implements the same functionality as FiveDayForecastForAllLocations but decodes
+// the locations from the response one at a time as it is read, calling fn with each. This avoids holding the whole
+// response in memory, as long as the parameters and data date come before the locations as they do from the service.
+// If fn returns an error decoding stops and the error is returned.
+//
+// In DecodingModeLenient and DecodingModeStrict each location is checked against the schema as it is decoded.
+// Differences which apply to the whole response are attached to the first location, or are only reported in
+// DecodingModeStrict if they are found after it has been passed to fn
+func (d *DataPointClient) FiveDayForecastForAllLocationsFunc(resolution Resolution, at *time.Time, fn func(rep SiteRep) error) error {
+	params := map[string]string{
+		"res": string(resolution),
+	}
+	if at != nil {
+		params["time"] = at.Format(time.RFC3339)
+	}
+	body, target, err := d.open("locationId", "val/wxfcs/all/json/all", params)
+	if err != nil {
+		return err
+	}
+	defer closeBody(body, "locationId")
+
+	stream := &locationStream{client: d, resolution: resolution, target: target, fn: fn}
+	err = stream.decode(json.NewDecoder(body))
+	if stream.stopped != nil {
+		return stream.stopped
+	}
+	if err != nil {
+		return fmt.Errorf("failed to deserialise body from %v for all locations: %w", target, err)
+	}
+	return nil
+}
+
+// locationStream decodes the locations of an all locations response as they are read. Locations which are read before
+// the parameters, data date and type are held until all three are known
+type locationStream struct {
+	client     *DataPointClient
+	resolution Resolution
+	target     string
+	fn         func(rep SiteRep) error
+
+	params    map[string]ParameterDescriptor
+	startTime *time.Time
+	typeName  *string
+
+	pending []json.RawMessage
+	count   int
+	shared  []Warning
+	// stopped holds the error which stopped decoding if it did not come from reading the response, such as an error
+	// returned from fn, so that it can be returned as it is
+	stopped error
+}
+
+func (s *locationStream) decode(dec *json.Decoder) error {
+	err := s.object(dec, "", func(key string, path string) error {
+		if key != "SiteRep" {
+			return s.skip(dec, path)
+		}
+		return s.object(dec, path, func(key string, path string) error {
+			switch key {
+			case "Wx":
+				return s.wx(dec, path)
+			case "DV":
+				return s.dv(dec, path)
+			default:
+				return s.skip(dec, path)
+			}
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.params == nil {
+		s.warn("SiteRep.Wx", "missing field")
+		s.params = map[string]ParameterDescriptor{}
+	}
+	if s.typeName == nil {
+		s.warn("SiteRep.DV.type", "missing field")
+		s.typeName = new(string)
+	}
+	if s.startTime == nil {
+		return errors.New("failed to parse period start time: no data date")
+	}
+	if err := s.flush(); err != nil {
+		return err
+	}
+	if s.client.decodingMode == DecodingModeStrict && len(s.shared) > 0 {
+		s.stopped = (&decoder{mode: DecodingModeStrict, warnings: s.shared}).strictError(s.target)
+		return s.stopped
+	}
+	return nil
+}
+
+func (s *locationStream) dv(dec *json.Decoder, path string) error {
+	return s.object(dec, path, func(key string, path string) error {
+		switch key {
+		case "dataDate":
+			var value string
+			if err := dec.Decode(&value); err != nil {
+				return err
+			}
+			startTime, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return fmt.Errorf("failed to parse period start time: %w", err)
+			}
+			s.startTime = &startTime
+		case "type":
+			var value string
+			if err := dec.Decode(&value); err != nil {
+				return err
+			}
+			s.typeName = &value
+		case "Location":
+			return s.locations(dec)
+		default:
+			return s.skip(dec, path)
+		}
+		return s.flush()
+	})
+}
+
+func (s *locationStream) wx(dec *json.Decoder, path string) error {
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	var wx wxResponse
+	if err := json.Unmarshal(raw, &wx); err != nil {
+		return err
+	}
+	s.check(raw, wx, path)
+
+	s.params = map[string]ParameterDescriptor{}
+	for _, p := range wx.Param {
+		s.params[p.Name] = ParameterDescriptor{
+			Name:        p.Name,
+			Units:       p.Units,
+			Description: p.Description,
+		}
+	}
+	return s.flush()
+}
+
+// locations reads the Location array, or the bare object returned in its place when there is a single location
+func (s *locationStream) locations(dec *json.Decoder) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch token {
+	case json.Delim('['):
+		for dec.More() {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			if err := s.add(raw); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+		return err
+	case json.Delim('{'):
+		fields := map[string]json.RawMessage{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			fields[fmt.Sprint(key)] = raw
+		}
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+		raw, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		return s.add(raw)
+	default:
+		return fmt.Errorf("expected Location to be an array or object but found %v", token)
+	}
+}
+
+func (s *locationStream) add(raw json.RawMessage) error {
+	s.pending = append(s.pending, raw)
+	return s.flush()
+}
+
+// flush converts and passes on every pending location once the parameters, data date and type are known
+func (s *locationStream) flush() error {
+	if s.params == nil || s.startTime == nil || s.typeName == nil {
+		return nil
+	}
+	for len(s.pending) > 0 {
+		raw := s.pending[0]
+		s.pending = s.pending[1:]
+		if err := s.emit(raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *locationStream) emit(raw json.RawMessage) error {
+	path := fmt.Sprintf("SiteRep.DV.Location[%d]", s.count)
+	var entry locationEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return fmt.Errorf("failed to deserialise %v: %w", path, err)
+	}
+
+	dec := s.client.newDecoder()
+	if s.count == 0 {
+		dec.warnings = s.shared
+		s.shared = nil
+	}
+	s.count++
+	if dec.checking() {
+		var value any
+		if err := json.Unmarshal(raw, &value); err == nil {
+			dec.checkValue(value, reflect.TypeOf(entry), path)
+		}
+	}
+
+	rep, err := convertLocation(dec, path, s.client.parameters, s.params, *s.typeName, s.resolution, *s.startTime, entry)
+	if err != nil {
+		return err
+	}
+	rep.Warnings, err = dec.result(s.target)
+	if err == nil {
+		err = s.fn(*rep)
+	}
+	if err != nil {
+		s.stopped = err
+	}
+	return err
+}
+
+// object reads a JSON object, calling field for each of its keys with the decoder positioned at the value
+func (s *locationStream) object(dec *json.Decoder, path string, field func(key string, path string) error) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != json.Delim('{') {
+		return fmt.Errorf("expected an object at %v but found %v", path, token)
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if err := field(fmt.Sprint(key), joinPath(path, fmt.Sprint(key))); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// skip reads past a value which is not part of the expected schema
+func (s *locationStream) skip(dec *json.Decoder, path string) error {
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	s.warn(path, "unknown field")
+	return nil
+}
+
+func (s *locationStream) check(raw json.RawMessage, v any, path string) {
+	dec := s.client.newDecoder()
+	if !dec.checking() {
+		return
+	}
+	var value any
+	if err := json.Unmarshal(raw, &value); err == nil {
+		dec.checkValue(value, reflect.TypeOf(v), path)
+	}
+	s.shared = append(s.shared, dec.warnings...)
+}
+
+// warn records a difference which applies to the whole response
+func (s *locationStream) warn(path string, message string) {
+	if s.client.decodingMode != DecodingModeDefault {
+		s.shared = append(s.shared, Warning{Path: path, Message: message})
+	}
+}
@@ -0,0 +1,99 @@
+package datapoint
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// visibilityBands lists the known bands in order from the worst to the best visibility along with the official range
+// of distances, in metres, they cover. Each band includes its minimum and excludes its maximum
+var visibilityBands = []struct {
+	band Visibility
+	min  float64
+	max  float64
+}{
+	{VisibilityVeryPoor, 0, 1000},
+	{VisibilityPoor, 1000, 4000},
+	{VisibilityModerate, 4000, 10000},
+	{VisibilityGood, 10000, 20000},
+	{VisibilityVeryGood, 20000, 40000},
+	{VisibilityExcellent, 40000, math.Inf(1)},
+}
+
+// ParseVisibility converts a visibility as it is returned by DataPoint into a Visibility. Forecasts return one of the
+// band codes such as 'VP' or 'GO' while observations return a distance in metres, which is placed into its band
+func ParseVisibility(value string) (Visibility, error) {
+	code := Visibility(strings.ToUpper(strings.TrimSpace(value)))
+	if code == VisibilityUnknown || code.Rank() > 0 {
+		return code, nil
+	}
+
+	metres, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return VisibilityUnknown, fmt.Errorf("failed to parse visibility %v: %w", value, err)
+	}
+	if metres < 0 || math.IsNaN(metres) {
+		return VisibilityUnknown, fmt.Errorf("visibility %v is negative", value)
+	}
+	return VisibilityFromMetres(metres), nil
+}
+
+// VisibilityFromMetres returns the band which contains a visibility distance in metres, as reported by observations.
+// Negative distances return VisibilityUnknown
+func VisibilityFromMetres(metres float64) Visibility {
+	for _, b := range visibilityBands {
+		if metres >= b.min && metres < b.max {
+			return b.band
+		}
+	}
+	return VisibilityUnknown
+}
+
+// Range returns the official range of distances in metres covered by the band. The minimum is inclusive and the
+// maximum exclusive, with VisibilityExcellent having an infinite maximum. VisibilityUnknown and unrecognised codes
+// return NaN for both values
+func (v Visibility) Range() (float64, float64) {
+	for _, b := range visibilityBands {
+		if b.band == v {
+			return b.min, b.max
+		}
+	}
+	return math.NaN(), math.NaN()
+}
+
+// Rank orders the bands from VisibilityVeryPoor (1) to VisibilityExcellent (6). VisibilityUnknown and unrecognised
+// codes return 0
+func (v Visibility) Rank() int {
+	for i, b := range visibilityBands {
+		if b.band == v {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Compare returns -1 if this band has worse visibility than the other, 1 if it is better and 0 if they are the same.
+// VisibilityUnknown compares as worse than every known band
+func (v Visibility) Compare(other Visibility) int {
+	a, b := v.Rank(), other.Rank()
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// IsBetterThan returns if this band has better visibility than the other
+func (v Visibility) IsBetterThan(other Visibility) bool {
+	return v.Compare(other) > 0
+}
+
+// IsWorseThan returns if this band has worse visibility than the other
+func (v Visibility) IsWorseThan(other Visibility) bool {
+	return v.Compare(other) < 0
+}
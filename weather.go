@@ -0,0 +1,186 @@
+package datapoint
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// weatherTypeNotAvailableCode is the value DataPoint returns in place of a weather type when none is available
+const weatherTypeNotAvailableCode = "NA"
+
+type weatherTypeInfo struct {
+	severity int
+	day      WeatherType
+	night    WeatherType
+}
+
+// weatherTypes holds the day and night variants of each weather type. The severity orders the types from the most
+// benign to the most hazardous. The official descriptions are held in the English catalogue
+var weatherTypes = map[WeatherType]weatherTypeInfo{
+	WeatherTypeNotAvailable:         {-1, WeatherTypeNotAvailable, WeatherTypeNotAvailable},
+	WeatherTypeClearNight:           {0, WeatherTypeSunnyDay, WeatherTypeClearNight},
+	WeatherTypeSunnyDay:             {0, WeatherTypeSunnyDay, WeatherTypeClearNight},
+	WeatherTypePartlyCloudyNight:    {1, WeatherTypePartlyCloudyDay, WeatherTypePartlyCloudyNight},
+	WeatherTypePartlyCloudyDay:      {1, WeatherTypePartlyCloudyDay, WeatherTypePartlyCloudyNight},
+	WeatherTypeNotUsed:              {-1, WeatherTypeNotUsed, WeatherTypeNotUsed},
+	WeatherTypeMist:                 {4, WeatherTypeMist, WeatherTypeMist},
+	WeatherTypeFog:                  {5, WeatherTypeFog, WeatherTypeFog},
+	WeatherTypeCloudy:               {2, WeatherTypeCloudy, WeatherTypeCloudy},
+	WeatherTypeOvercast:             {3, WeatherTypeOvercast, WeatherTypeOvercast},
+	WeatherTypeLightRainShowerNight: {6, WeatherTypeLightRainShowerDay, WeatherTypeLightRainShowerNight},
+	WeatherTypeLightRainShowerDay:   {6, WeatherTypeLightRainShowerDay, WeatherTypeLightRainShowerNight},
+	WeatherTypeDrizzle:              {6, WeatherTypeDrizzle, WeatherTypeDrizzle},
+	WeatherTypeLightRain:            {7, WeatherTypeLightRain, WeatherTypeLightRain},
+	WeatherTypeHeavyRainShowerNight: {8, WeatherTypeHeavyRainShowerDay, WeatherTypeHeavyRainShowerNight},
+	WeatherTypeHeavyRainShowerDay:   {8, WeatherTypeHeavyRainShowerDay, WeatherTypeHeavyRainShowerNight},
+	WeatherTypeHeavyRain:            {9, WeatherTypeHeavyRain, WeatherTypeHeavyRain},
+	WeatherTypeSleetShowerNight:     {10, WeatherTypeSleetShowerDay, WeatherTypeSleetShowerNight},
+	WeatherTypeSleetShowerDay:       {10, WeatherTypeSleetShowerDay, WeatherTypeSleetShowerNight},
+	WeatherTypeSleet:                {11, WeatherTypeSleet, WeatherTypeSleet},
+	WeatherTypeHailShowerNight:      {14, WeatherTypeHailShowerDay, WeatherTypeHailShowerNight},
+	WeatherTypeHailShowerDay:        {14, WeatherTypeHailShowerDay, WeatherTypeHailShowerNight},
+	WeatherTypeHail:                 {15, WeatherTypeHail, WeatherTypeHail},
+	WeatherTypeLightSnowShowerNight: {12, WeatherTypeLightSnowShowerDay, WeatherTypeLightSnowShowerNight},
+	WeatherTypeLightSnowShowerDay:   {12, WeatherTypeLightSnowShowerDay, WeatherTypeLightSnowShowerNight},
+	WeatherTypeLightSnow:            {13, WeatherTypeLightSnow, WeatherTypeLightSnow},
+	WeatherTypeHeavySnowShowerNight: {16, WeatherTypeHeavySnowShowerDay, WeatherTypeHeavySnowShowerNight},
+	WeatherTypeHeavySnowShowerDay:   {16, WeatherTypeHeavySnowShowerDay, WeatherTypeHeavySnowShowerNight},
+	WeatherTypeHeavySnow:            {17, WeatherTypeHeavySnow, WeatherTypeHeavySnow},
+	WeatherTypeThunderShowerNight:   {18, WeatherTypeThunderShowerDay, WeatherTypeThunderShowerNight},
+	WeatherTypeThunderShowerDay:     {18, WeatherTypeThunderShowerDay, WeatherTypeThunderShowerNight},
+	WeatherTypeThunder:              {19, WeatherTypeThunder, WeatherTypeThunder},
+}
+
+// ParseWeatherType converts a weather type as it is returned by DataPoint into a WeatherType. The 'NA' value is
+// returned as WeatherTypeNotAvailable
+func ParseWeatherType(value string) (WeatherType, error) {
+	if value == weatherTypeNotAvailableCode {
+		return WeatherTypeNotAvailable, nil
+	}
+
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return WeatherTypeNotAvailable, fmt.Errorf("failed to parse weather type %v: %w", value, err)
+	}
+
+	w := WeatherType(v)
+	if !w.IsValid() {
+		return WeatherTypeNotAvailable, fmt.Errorf("unknown weather type %v", value)
+	}
+	return w, nil
+}
+
+// IsValid returns if this is one of the weather types defined by DataPoint, including WeatherTypeNotAvailable and
+// WeatherTypeNotUsed
+func (w WeatherType) IsValid() bool {
+	_, ok := weatherTypes[w]
+	return ok
+}
+
+// IsKnown returns if this weather type describes actual weather, which excludes WeatherTypeNotAvailable,
+// WeatherTypeNotUsed and any code which is not defined
+func (w WeatherType) IsKnown() bool {
+	return w.IsValid() && w != WeatherTypeNotAvailable && w != WeatherTypeNotUsed
+}
+
+// String returns the official Met Office description of the weather type, e.g. 'Light rain'. Codes which are not
+// defined return 'Unknown' followed by the code
+func (w WeatherType) String() string {
+	return w.Localise(LanguageEnglish)
+}
+
+// Code returns the value used to represent this weather type in DataPoint responses
+func (w WeatherType) Code() string {
+	if w == WeatherTypeNotAvailable {
+		return weatherTypeNotAvailableCode
+	}
+	return strconv.Itoa(int(w))
+}
+
+// Day returns the day time variant of this weather type, or the weather type itself if it has no variants
+func (w WeatherType) Day() WeatherType {
+	info, ok := weatherTypes[w]
+	if !ok {
+		return w
+	}
+	return info.day
+}
+
+// Night returns the night time variant of this weather type, or the weather type itself if it has no variants
+func (w WeatherType) Night() WeatherType {
+	info, ok := weatherTypes[w]
+	if !ok {
+		return w
+	}
+	return info.night
+}
+
+// IsDay returns if this is the day time variant of a weather type which has separate day and night variants
+func (w WeatherType) IsDay() bool {
+	return w.Day() == w && w.Night() != w
+}
+
+// IsNight returns if this is the night time variant of a weather type which has separate day and night variants
+func (w WeatherType) IsNight() bool {
+	return w.Night() == w && w.Day() != w
+}
+
+// Severity returns a rank which orders weather types from the most benign (clear skies, 0) to the most hazardous
+// (thunder). Day and night variants share a severity. Types which do not describe weather return -1
+func (w WeatherType) Severity() int {
+	info, ok := weatherTypes[w]
+	if !ok {
+		return -1
+	}
+	return info.severity
+}
+
+// IsClear returns if the weather type describes clear skies
+func (w WeatherType) IsClear() bool {
+	return w == WeatherTypeClearNight || w == WeatherTypeSunnyDay
+}
+
+// IsCloud returns if the weather type describes cloud without precipitation
+func (w WeatherType) IsCloud() bool {
+	return w == WeatherTypePartlyCloudyNight || w == WeatherTypePartlyCloudyDay || w == WeatherTypeCloudy || w == WeatherTypeOvercast
+}
+
+// IsFog returns if the weather type describes mist or fog
+func (w WeatherType) IsFog() bool {
+	return w == WeatherTypeMist || w == WeatherTypeFog
+}
+
+// IsPrecipitation returns if the weather type describes any form of precipitation
+func (w WeatherType) IsPrecipitation() bool {
+	return w >= WeatherTypeLightRainShowerNight && w <= WeatherTypeThunder
+}
+
+// IsRain returns if the weather type describes rain or drizzle
+func (w WeatherType) IsRain() bool {
+	return w >= WeatherTypeLightRainShowerNight && w <= WeatherTypeHeavyRain
+}
+
+// IsSleet returns if the weather type describes sleet
+func (w WeatherType) IsSleet() bool {
+	return w >= WeatherTypeSleetShowerNight && w <= WeatherTypeSleet
+}
+
+// IsHail returns if the weather type describes hail
+func (w WeatherType) IsHail() bool {
+	return w >= WeatherTypeHailShowerNight && w <= WeatherTypeHail
+}
+
+// IsSnow returns if the weather type describes snow
+func (w WeatherType) IsSnow() bool {
+	return w >= WeatherTypeLightSnowShowerNight && w <= WeatherTypeHeavySnow
+}
+
+// IsThunder returns if the weather type describes thunder
+func (w WeatherType) IsThunder() bool {
+	return w >= WeatherTypeThunderShowerNight && w <= WeatherTypeThunder
+}
+
+// IsShower returns if the weather type describes showers rather than persistent precipitation
+func (w WeatherType) IsShower() bool {
+	return w.IsPrecipitation() && (w.IsDay() || w.IsNight())
+}